@@ -1,11 +1,12 @@
 package fiqlsqladapter
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	fq "github.com/eisenwinter/fiql-parser"
 )
@@ -29,29 +30,74 @@ func concatErrrors(errs []error) error {
 // carry the same columns - but i would not recommend sharing
 // adapters for multiple  tables
 type Adapter struct {
-	fields     FieldMapping
-	parser     *fq.Parser
-	delim      delimiterStyle
-	paramStyle paramStyle
-	concat     concatSupport
-	tableName  string
+	sqlDialect
+	fields            FieldMapping
+	parser            *fq.Parser
+	aliasParamNames   bool
+	namedParams       paramStyle
+	tableName         string
+	limits            queryLimits
+	wildcards         WildcardPolicy
+	minWildcardLength int
+	caseInsensitive   bool
+	authorizer        Authorizer
+	scopes            []scope
+	defaultOrder      string
+	tieBreaker        string
+	cursorSecret      []byte
+	pageSize          int
+	maxPageSize       int
+	relations         map[string]Relation
+	err               error
 }
 
 type whereBuilder struct {
-	sb           strings.Builder
-	params       []interface{}
-	errors       []error
-	lastSelector *Field
-	fields       FieldMapping
-	delim        delimiterStyle
-	paramStyle   paramStyle
-	concat       concatSupport
-	tableName    string
+	sb             strings.Builder
+	params         []interface{}
+	aliases        []string
+	errors         []error
+	lastSelector   *Field
+	lastComparison fq.ComparisonDefintion
+	lastColumn     string
+	fields         FieldMapping
+	permitted      func(Field) bool
+	sqlDialect
+	tableName         string
+	limits            queryLimits
+	wildcards         WildcardPolicy
+	minWildcardLength int
+	caseInsensitive   bool
+	used              []Field
+	exists            map[string]string
+	groups            []existsGroup
+	depth             int
+	comparisons       int
+	usage             dialectUsage
+	limitErr          *Error
+}
+
+func (t *whereBuilder) exceeded(kind ErrorKind, limit int) {
+	if t.limitErr == nil {
+		t.limitErr = limitError(kind, limit)
+	}
+}
+
+func (t *whereBuilder) VisitExpressionEntered() {
+	t.depth++
+	if exceeds(t.limits.depth, t.depth) {
+		t.exceeded(ErrorKindQueryTooDeep, t.limits.depth)
+	}
+	t.enterExistsGroup()
+	t.sb.WriteString("(")
+}
+
+func (t *whereBuilder) VisitExpressionLeft() {
+	t.depth--
+	t.leaveExistsGroup()
 }
 
-func (t *whereBuilder) VisitExpressionEntered() { t.sb.WriteString("(") }
-func (t *whereBuilder) VisitExpressionLeft()    { t.sb.WriteString(")") }
 func (t *whereBuilder) VisitOperator(operatorCtx fq.OperatorContext) {
+	t.endExistsSpan()
 	switch operatorCtx.Operator() {
 	case fq.OperatorAND:
 		t.sb.WriteString(" AND ")
@@ -62,35 +108,70 @@ func (t *whereBuilder) VisitOperator(operatorCtx fq.OperatorContext) {
 
 func (t *whereBuilder) VisitSelector(selectorCtx fq.SelectorContext) {
 	selector := selectorCtx.Selector()
-	if fi, ok := t.fields[strings.ToLower(selector)]; ok {
-		if fi.TablePrefix != "" {
-			delimitBuilder(t.delim, fi.TablePrefix, &t.sb)
-			t.sb.WriteRune('.')
-		} else if t.tableName != "" {
-			delimitBuilder(t.delim, t.tableName, &t.sb)
-			t.sb.WriteRune('.')
-		}
-		delimitBuilder(t.delim, fi.Db, &t.sb)
-		if selectorCtx.IsUnary() {
-			t.lastSelector = nil
-			t.sb.WriteString(" IS NOT NULL")
-		} else {
-			t.lastSelector = &fi
-		}
-
-	} else {
-		t.errors = append(t.errors, fmt.Errorf("invalid selector: %s", selector))
+	t.comparisons++
+	if exceeds(t.limits.comparisons, t.comparisons) {
+		t.exceeded(ErrorKindTooManyComparisons, t.limits.comparisons)
+	}
+	fi, ok := t.fields[strings.ToLower(selector)]
+	if !ok || !t.permitted(fi) {
+		t.addExistsSpan("")
+		t.errors = append(t.errors, newError(ErrorKindInvalidSelector, selector, nil))
 		t.lastSelector = nil
+		return
+	}
+	relation := ""
+	if _, many := t.exists[fi.relation]; many {
+		relation = fi.relation
+	}
+	t.addExistsSpan(relation)
+	if !fi.Filterable() {
+		t.errors = append(t.errors, newError(ErrorKindNotFilterable, selector, nil))
+		t.lastSelector = nil
+		return
 	}
+	t.used = append(t.used, fi)
+	var col strings.Builder
+	fieldBuilder(t.tableName, fi, &col)
+	if selectorCtx.IsUnary() {
+		t.lastSelector = nil
+		t.sb.WriteString(col.String())
+		t.sb.WriteString(" IS NOT NULL")
+	} else {
+		t.lastSelector = &fi
+		t.lastColumn = col.String()
+	}
+}
 
+var comparisonNames = map[fq.ComparisonDefintion]Comparison{
+	fq.ComparisonEq:  ComparisonEq,
+	fq.ComparisonNeq: ComparisonNe,
+	fq.ComparisonGt:  ComparisonGt,
+	fq.ComparisonGte: ComparisonGe,
+	fq.ComparisonLt:  ComparisonLt,
+	fq.ComparisonLte: ComparisonLe,
 }
+
 func (t *whereBuilder) VisitComparison(comparisonCtx fq.ComparisonContext) {
 	if t.lastSelector == nil {
 		return
 	}
-	switch comparisonCtx.Comparison() {
+	if !t.lastSelector.Allows(comparisonNames[comparisonCtx.Comparison()]) || !t.arrayComparable(comparisonCtx.Comparison()) {
+		t.errors = append(t.errors, newError(ErrorKindComparisonNotAllowed, t.lastSelector.Alias, nil))
+		t.lastSelector = nil
+		return
+	}
+	t.lastComparison = comparisonCtx.Comparison()
+}
+
+// comparisonBuilder writes the last comparison, it is written once the argument
+// is known as some dialects need to handle certain arguments differently
+func (t *whereBuilder) comparisonBuilder() {
+	switch t.lastComparison {
 	case fq.ComparisonEq:
-		if t.lastSelector.Type == stringType {
+		if t.lastSelector.Type == stringType && t.caseInsensitive && t.ilike {
+			t.usage.ilike = true
+			t.sb.WriteString(" ILIKE ")
+		} else if t.lastSelector.Type == stringType {
 			t.sb.WriteString(" LIKE ")
 		} else {
 			t.sb.WriteString(" = ")
@@ -118,6 +199,9 @@ func (t *whereBuilder) isCompatibleType(from, to reflect.Type) bool {
 
 func (t *whereBuilder) negotiateArgumentType(args *fq.ArgumentContext) (bool, error) {
 	exp := t.lastSelector.Type
+	if isArrayType(exp) {
+		exp = exp.Elem()
+	}
 	if args.ValueRecommendation() == fq.ValueRecommendationString && isPointerCompatibleType(exp, stringType) {
 		//its safe to assume that string is a string
 		t.params = append(t.params, args.AsString())
@@ -184,102 +268,136 @@ func (t *whereBuilder) VisitArgument(argumentCtx fq.ArgumentContext) {
 	s, err := t.negotiateArgumentType(&argumentCtx)
 	if err != nil {
 		t.lastSelector = nil
-		t.errors = append(t.errors, fmt.Errorf("invalid type of argument: %s", argumentCtx.AsString()))
+		t.errors = append(t.errors, newError(ErrorKindInvalidArgument, argumentCtx.AsString(), err))
 		return
 	}
-
-	if s && (argumentCtx.StartsWithWildcard() || argumentCtx.EndsWithWildcard()) {
-		if t.concat == concatFunctionSupported {
-			t.sb.WriteString("CONCAT(")
+	t.aliases = append(t.aliases, t.lastSelector.Alias)
+	if exceeds(t.limits.params, len(t.params)) {
+		t.exceeded(ErrorKindTooManyParameters, t.limits.params)
+	}
+	if isArrayType(t.lastSelector.Type) {
+		t.arrayBuilder(argumentCtx.StartsWithWildcard() || argumentCtx.EndsWithWildcard())
+		return
+	}
+	t.sb.WriteString(t.lastColumn)
+	if s {
+		if err := t.checkWildcards(argumentCtx.StartsWithWildcard(), argumentCtx.EndsWithWildcard(), argumentCtx.AsString()); err != nil {
+			t.errors = append(t.errors, err)
+			return
 		}
 	}
-	if s && argumentCtx.StartsWithWildcard() {
-		if t.concat == concatFunctionSupported {
-			t.sb.WriteString("'%',")
-		} else {
-			t.sb.WriteString("'%' || ")
+	if s && argumentCtx.AsString() == "" && !argumentCtx.StartsWithWildcard() && !argumentCtx.EndsWithWildcard() {
+		t.usage.emptyString = true
+	}
+	if s && t.emptyStringIsNull && argumentCtx.AsString() == "" && !argumentCtx.StartsWithWildcard() && !argumentCtx.EndsWithWildcard() {
+		switch t.lastComparison {
+		case fq.ComparisonEq:
+			t.dropLastParameter()
+			t.sb.WriteString(" IS NULL")
+			return
+		case fq.ComparisonNeq:
+			t.dropLastParameter()
+			t.sb.WriteString(" IS NOT NULL")
+			return
 		}
+	}
+	t.comparisonBuilder()
 
+	wildcard := s && (argumentCtx.StartsWithWildcard() || argumentCtx.EndsWithWildcard())
+	if wildcard {
+		t.sb.WriteRune(concatStart)
+	}
+	if s && argumentCtx.StartsWithWildcard() {
+		t.sb.WriteString("'%'")
+		t.sb.WriteRune(concatSeparator)
 	}
-	parameterBuilder(t.paramStyle, len(t.params), &t.sb)
+	t.argumentPlaceholder(&t.sb)
 	if s && argumentCtx.EndsWithWildcard() {
-		if t.concat == concatFunctionSupported {
-			t.sb.WriteString(",'%'")
-		} else {
-			t.sb.WriteString(" || '%'")
-		}
-
+		t.sb.WriteRune(concatSeparator)
+		t.sb.WriteString("'%'")
 	}
-	if s && (argumentCtx.StartsWithWildcard() || argumentCtx.EndsWithWildcard()) {
-		if t.concat == concatFunctionSupported {
-			t.sb.WriteString(")")
-		}
+	if wildcard {
+		t.sb.WriteRune(concatEnd)
 	}
+}
 
+// dropLastParameter removes the last parameter if it is not needed after all
+func (t *whereBuilder) dropLastParameter() {
+	t.params = t.params[:len(t.params)-1]
+	t.aliases = t.aliases[:len(t.aliases)-1]
 }
 
 // Where generates a where predicate from a given fiql query
 func (a *Adapter) Where(query string) (*WherePredicate, error) {
+	return a.WhereContext(context.Background(), query)
+}
+
+// WhereContext generates a where predicate from a given fiql query,
+// selectors the permissions of the context do not allow are treated as unknown
+func (a *Adapter) WhereContext(ctx context.Context, query string) (*WherePredicate, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	return a.buildWhere(ctx, query, a.fields, a.scopes)
+}
+
+// buildWhere generates a predicate for the given fields, the scopes are
+// appended to the predicate
+func (a *Adapter) buildWhere(ctx context.Context, query string, fields FieldMapping, scopes []scope) (*WherePredicate, error) {
+	if exceeds(a.limits.length, utf8.RuneCountInString(query)) {
+		return nil, limitError(ErrorKindQueryTooLong, a.limits.length)
+	}
 	ast, err := a.parser.Parse(query)
 	if err != nil {
-		return nil, err
+		return nil, newError(ErrorKindSyntax, query, err)
 	}
-	wb := whereBuilder{
-		fields:     a.fields,
-		params:     make([]interface{}, 0),
-		errors:     make([]error, 0),
-		delim:      a.delim,
-		paramStyle: a.paramStyle,
-		concat:     a.concat,
-		tableName:  a.tableName,
+	wb := a.newWhereBuilder(ctx)
+	if len(wb.exists) > 0 && a.tableName == "" {
+		// the subqueries are correlated by the qualified local key
+		return nil, newError(ErrorKindConfiguration, "table name", nil)
+	}
+	wb.fields = fields
+	ast.Accept(wb)
+	if wb.limitErr != nil {
+		return nil, wb.limitErr
 	}
-	ast.Accept(&wb)
 	if len(wb.errors) > 0 {
 		return nil, concatErrrors(wb.errors)
 	}
-	return &WherePredicate{
-		sql:    wb.sb.String(),
-		params: wb.params,
-	}, nil
+	if err := wb.scopeBuilder(ctx, scopes); err != nil {
+		return nil, err
+	}
+	return a.predicate(wb), nil
 }
 
-// OrderBy generates a order by clause from a given query
-// this is no fiql but rather the format ([+|-|])ALIAS[;([+|-|])ALIAS]*
-func (a *Adapter) OrderBy(query string) (*OrderByClause, error) {
-	if query == "" {
-		return &OrderByClause{}, nil
-	}
-	var sb strings.Builder
-	s := strings.Split(query, ";")
-	for i, v := range s {
-		if len(v) > 0 {
-			prefixed := v[0] == '-' || v[0] == '+'
-			var fn string
-			if prefixed {
-				fn = strings.ToLower(v[1:])
-			} else {
-				fn = strings.ToLower(v)
-			}
-			if f, ok := a.fields[fn]; ok {
-				delimitBuilder(a.delim, f.Db, &sb)
-				if v[0] != '-' {
-					sb.WriteString(" ASC")
-					if i != len(s)-1 {
-						sb.WriteString(", ")
-					}
-					continue
-				} else {
-					sb.WriteString(" DESC")
-					if i != len(s)-1 {
-						sb.WriteString(", ")
-					}
-					continue
-				}
-			}
-		}
-		return nil, errors.New("invalid order by selector")
+func (a *Adapter) newWhereBuilder(ctx context.Context) *whereBuilder {
+	return &whereBuilder{
+		fields:            a.fields,
+		permitted:         a.permitted(ctx),
+		params:            make([]interface{}, 0),
+		errors:            make([]error, 0),
+		sqlDialect:        a.sqlDialect,
+		tableName:         a.tableName,
+		limits:            a.limits,
+		wildcards:         a.wildcards,
+		minWildcardLength: a.minWildcardLength,
+		caseInsensitive:   a.caseInsensitive,
+		exists:            a.existsPrefix(),
+	}
+}
+
+func (a *Adapter) predicate(wb *whereBuilder) *WherePredicate {
+	return &WherePredicate{
+		sql:        wb.sb.String(),
+		params:     wb.params,
+		aliases:    wb.aliases,
+		style:      a.params(),
+		aliasNames: a.aliasParamNames,
+		delim:      a.delim,
+		concat:     a.concat,
+		usage:      wb.usage.of(a.sqlDialect),
+		joins:      a.joinsFor(wb.used),
 	}
-	return &OrderByClause{sql: sb.String()}, nil
 }
 
 // Update updates the given adapters supplied options
@@ -289,25 +407,44 @@ func (a *Adapter) Update(options ...func(*Adapter)) {
 	for _, o := range options {
 		o(a)
 	}
+	a.validate()
+}
+
+// check returns the error of an unknown dialect or an invalid configuration
+func (a *Adapter) check() error {
+	if a.sqlDialect.err != nil {
+		return a.sqlDialect.err
+	}
+	return a.err
+}
+
+// validate reports an invalid field mapping as adapter error
+func (a *Adapter) validate() {
+	if a.err != nil {
+		return
+	}
+	a.err = validateMapping(a.fields)
 }
 
 // NewAdapter returns a new fiql adapter for the given field mapping
 // use the MappingBuilder to create field mapping
 func NewAdapter(mapping FieldMapping, options ...func(*Adapter)) *Adapter {
-	adapter := &Adapter{fields: mapping, parser: fq.NewParser(), paramStyle: standardParamStyle, delim: standardSqlDelimiter}
+	adapter := &Adapter{fields: mapping, parser: fq.NewParser(), sqlDialect: sqlDialect{paramStyle: standardParamStyle, delim: standardSqlDelimiter}}
 	for _, o := range options {
 		o(adapter)
 	}
+	adapter.validate()
 	return adapter
 }
 
 // NewAdapterFor creates a new adapter from struct tags of the typeDef argument
 func NewAdapterFor(typeDef interface{}, options ...func(*Adapter)) *Adapter {
 	mapping := tagsFromStruct(typeDef)
-	adapter := &Adapter{fields: mapping, parser: fq.NewParser(), paramStyle: standardParamStyle, delim: standardSqlDelimiter}
+	adapter := &Adapter{fields: mapping, parser: fq.NewParser(), sqlDialect: sqlDialect{paramStyle: standardParamStyle, delim: standardSqlDelimiter}}
 	for _, o := range options {
 		o(adapter)
 	}
+	adapter.validate()
 	return adapter
 }
 
@@ -442,3 +442,70 @@ func TestOrderByMixedWithNatural(t *testing.T) {
 	}
 	assert.Equal(t, "`columnA` ASC, `columnB` ASC", res.String())
 }
+
+func TestComparisonNotAllowed(t *testing.T) {
+	b := NewMappingBuilder().AddStringMapping("email", "email").AddIntMapping("age", "age").AllowComparisons("email", ComparisonEq).Build()
+	p := NewAdapter(b, WithDialectPostgres())
+	_, err := p.Where("email!=a")
+	assert.Error(t, err)
+	assert.Equal(t, "comparison not allowed on selector: email", err.Error())
+	res, err := p.Where("email==a;age=gt=1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("email" LIKE $1 AND "age" > $2)`, res.Sql())
+}
+
+func TestWildcardLeadingForbiddenGlobally(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithWildcardPolicy(WildcardsNoLeading))
+	_, err := adp.Where("tx==*001020")
+	assert.Error(t, err)
+	assert.Equal(t, "wildcard not allowed on selector: tx", err.Error())
+	res, err := adp.Where("tx==001020*")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("Tx" LIKE CONCAT($1,'%'))`, res.Sql())
+}
+
+func TestWildcardForbiddenOnField(t *testing.T) {
+	b := NewMappingBuilder().AddStringMapping("email", "email").RestrictWildcards("email", WildcardsForbidden, 0).Build()
+	adp := NewAdapter(b, WithDialectPostgres())
+	_, err := adp.Where("email==foo*")
+	assert.Error(t, err)
+	_, err = adp.Where("email==foo")
+	assert.NoError(t, err)
+}
+
+func TestWildcardMinLength(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithMinWildcardLength(3))
+	_, err := adp.Where("tx==00*")
+	assert.Error(t, err)
+	assert.Equal(t, "wildcard requires at least 3 characters", err.Error())
+	_, err = adp.Where("tx==00")
+	assert.NoError(t, err)
+	_, err = adp.Where("tx==001*")
+	assert.NoError(t, err)
+}
+
+func TestOrderByWithTableName(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"))
+	res, err := adp.OrderBy("-cre;id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "[orders].[created_at] DESC, [orders].[ID] ASC", res.String())
+}
+
+func TestOrderByWithTablePrefixFromTag(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyPtrOverrideStruct{}, WithDialectMariaDB(), WithTableName("contacts"))
+	res, err := adp.OrderBy("-firstName")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "`kontakte`.`first_name` DESC", res.String())
+}
@@ -0,0 +1,170 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// AggregateFunction is an aggregate function usable in aggregate queries
+type AggregateFunction string
+
+// AggregateCount counts the rows or the non NULL values of a field, allowed for all types
+const AggregateCount AggregateFunction = "count"
+
+// AggregateSum sums the values of a numeric field
+const AggregateSum AggregateFunction = "sum"
+
+// AggregateAvg averages the values of a numeric field
+const AggregateAvg AggregateFunction = "avg"
+
+// AggregateMin is the smallest value of a numeric, time or string field
+const AggregateMin AggregateFunction = "min"
+
+// AggregateMax is the largest value of a numeric, time or string field
+const AggregateMax AggregateFunction = "max"
+
+// resultType returns the type of the aggregate over values of type t,
+// nil if the function is not allowed for the type
+func (fn AggregateFunction) resultType(t reflect.Type) reflect.Type {
+	if fn == AggregateCount {
+		return intType
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	numeric := t == intType || t == float64Type
+	switch fn {
+	case AggregateSum:
+		if numeric {
+			return t
+		}
+	case AggregateAvg:
+		if numeric {
+			return float64Type
+		}
+	case AggregateMin, AggregateMax:
+		if numeric || t == timeType || t == stringType {
+			return t
+		}
+	}
+	return nil
+}
+
+// Aggregate generates an aggregate select list and group by clause
+// from a comma separated list of aliases to group by e.g. groupBy=cur
+// and a comma separated list of aggregates e.g. agg=sum(amt),count(*),
+// aggregates are named function_alias or count for count(*)
+func (a *Adapter) Aggregate(groupBy, aggregates string) (*AggregateClause, error) {
+	return a.AggregateContext(context.Background(), groupBy, aggregates)
+}
+
+// AggregateContext generates an aggregate select list and group by clause,
+// selectors the permissions of the context do not allow are treated as unknown
+func (a *Adapter) AggregateContext(ctx context.Context, groupBy, aggregates string) (*AggregateClause, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(groupBy) == "" && strings.TrimSpace(aggregates) == "" {
+		return nil, newError(ErrorKindInvalidAggregate, "", nil)
+	}
+	permitted := a.permitted(ctx)
+	var groups []Field
+	if strings.TrimSpace(groupBy) != "" {
+		var err error
+		if groups, err = a.parseSelect(groupBy, permitted); err != nil {
+			return nil, err
+		}
+	}
+	var aggs []Field
+	if strings.TrimSpace(aggregates) != "" {
+		var err error
+		if aggs, err = a.parseAggregates(aggregates, permitted); err != nil {
+			return nil, err
+		}
+	}
+	all := append(append(make([]Field, 0, len(groups)+len(aggs)), groups...), aggs...)
+	c := &AggregateClause{fields: make(FieldMapping, len(all))}
+	var sel, gb strings.Builder
+	aliases := make([]string, 0, len(all))
+	for i, f := range all {
+		if i > 0 {
+			sel.WriteString(", ")
+		}
+		fieldBuilder(a.tableName, f, &sel)
+		sel.WriteString(" AS ")
+		aliasBuilder(f.Alias, &sel)
+		aliases = append(aliases, f.Alias)
+		c.fields[strings.ToLower(f.Alias)] = f
+	}
+	for i, f := range groups {
+		if i > 0 {
+			gb.WriteString(", ")
+		}
+		fieldBuilder(a.tableName, f, &gb)
+	}
+	c.sel = &SelectClause{sql: sel.String(), aliases: aliases, joins: a.joinsFor(all), delim: a.delim}
+	c.groupBy = gb.String()
+	return c, nil
+}
+
+// parseAggregates parses function(alias) terms into fields
+// holding the aggregate expression
+func (a *Adapter) parseAggregates(query string, permitted func(Field) bool) ([]Field, error) {
+	s := strings.Split(query, ",")
+	fields := make([]Field, 0, len(s))
+	seen := make(map[string]bool, len(s))
+	for _, v := range s {
+		term := strings.TrimSpace(v)
+		open := strings.IndexRune(term, '(')
+		if open <= 0 || !strings.HasSuffix(term, ")") {
+			return nil, newError(ErrorKindInvalidAggregate, v, nil)
+		}
+		fn := AggregateFunction(strings.ToLower(term[:open]))
+		arg := strings.ToLower(strings.TrimSpace(term[open+1 : len(term)-1]))
+		var agg Field
+		if arg == "*" {
+			if fn != AggregateCount {
+				return nil, newError(ErrorKindInvalidAggregate, v, nil)
+			}
+			agg = Field{Alias: string(fn), Type: intType, Expression: "COUNT(*)"}
+		} else {
+			f, ok := a.fields[arg]
+			if !ok || !permitted(f) || a.many(f) {
+				return nil, newError(ErrorKindInvalidSelector, arg, nil)
+			}
+			t := fn.resultType(f.Type)
+			if t == nil {
+				return nil, newError(ErrorKindInvalidAggregate, v, nil)
+			}
+			var sb strings.Builder
+			sb.WriteString(strings.ToUpper(string(fn)))
+			sb.WriteString("(")
+			fieldBuilder(a.tableName, f, &sb)
+			sb.WriteString(")")
+			agg = Field{Alias: string(fn) + "_" + f.Alias, Type: t, Roles: f.Roles, Expression: strings.ReplaceAll(sb.String(), "{", "{{"), relation: f.relation}
+		}
+		if seen[agg.Alias] {
+			continue
+		}
+		seen[agg.Alias] = true
+		fields = append(fields, agg)
+	}
+	return fields, nil
+}
+
+// Having generates a having predicate from a fiql query referencing
+// the grouped fields and the aggregates of the clause e.g. sum_amt=gt=100,
+// scopes are not applied as they belong into the where predicate
+func (a *Adapter) Having(c *AggregateClause, query string) (*WherePredicate, error) {
+	return a.HavingContext(context.Background(), c, query)
+}
+
+// HavingContext generates a having predicate from a fiql query referencing
+// the grouped fields and the aggregates of the clause
+func (a *Adapter) HavingContext(ctx context.Context, c *AggregateClause, query string) (*WherePredicate, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	return a.buildWhere(ctx, query, c.fields, nil)
+}
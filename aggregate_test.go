@@ -0,0 +1,57 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateGroupBy(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	res, err := adp.Aggregate("cur", "sum(amt),count(*),MAX(cre)")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"Currency" AS "cur", (SUM("amount")) AS "sum_amt", (COUNT(*)) AS "count", (MAX("created_at")) AS "max_cre"`, res.Select().Sql())
+	assert.Equal(t, []string{"cur", "sum_amt", "count", "max_cre"}, res.Select().Aliases())
+	assert.Equal(t, `"Currency"`, res.GroupBy())
+}
+
+func TestAggregateHaving(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithScope("tenant_id", 7))
+	res, err := adp.Aggregate("cur", "avg(amt),count(*)")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	having, err := adp.Having(res, "count=gt=10;avg_amt=ge=2.5,cur==EUR")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := having.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `((COUNT(*)) > @1 AND (AVG([amount])) >= @2 OR [Currency] = @3)`, s)
+	assert.Equal(t, []interface{}{10, 2.5, "EUR"}, args)
+
+	_, err = adp.Having(res, "amt=gt=1")
+	assert.Error(t, err)
+}
+
+func TestAggregateInvalid(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	for _, v := range []string{"sum(tx)", "avg(cre)", "median(amt)", "sum(*)", "count", ""} {
+		_, err := adp.Aggregate("", v)
+		var e *Error
+		assert.True(t, errors.As(err, &e), v)
+		if e != nil {
+			assert.Equal(t, ErrorKindInvalidAggregate, e.Kind, v)
+		}
+	}
+	_, err := adp.Aggregate("nope", "count(*)")
+	assert.Error(t, err)
+	_, err = adp.Aggregate("", "min(nope)")
+	assert.Error(t, err)
+}
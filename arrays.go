@@ -0,0 +1,49 @@
+package fiqlsqladapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	fq "github.com/eisenwinter/fiql-parser"
+)
+
+// isArrayType returns true for slice fields which are mapped to array columns
+func isArrayType(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Slice
+}
+
+// arrayComparable returns false if the last selector is an array
+// and the comparison can not be applied to it in the current dialect
+func (t *whereBuilder) arrayComparable(c fq.ComparisonDefintion) bool {
+	if !isArrayType(t.lastSelector.Type) {
+		return true
+	}
+	return t.arrayContains != "" && (c == fq.ComparisonEq || c == fq.ComparisonNeq)
+}
+
+// arrayBuilder writes a contains check for array columns,
+// == checks if the array contains the argument and != if it does not
+func (t *whereBuilder) arrayBuilder(wildcard bool) {
+	if wildcard {
+		t.errors = append(t.errors, newError(ErrorKindWildcardNotAllowed, t.lastSelector.Alias, nil))
+		return
+	}
+	if t.lastComparison == fq.ComparisonNeq {
+		t.sb.WriteString("NOT ")
+	}
+	t.usage.arrayContains = t.arrayContains
+	var param strings.Builder
+	t.argumentPlaceholder(&param)
+	t.sb.WriteString(fmt.Sprintf(t.arrayContains, t.lastColumn, param.String()))
+}
+
+// argumentPlaceholder writes the placeholder of the last parameter
+func (t *whereBuilder) argumentPlaceholder(sb *strings.Builder) {
+	last := len(t.params) - 1
+	if _, ok := t.params[last].(time.Time); ok {
+		t.usage.times = true
+	}
+	t.params[last] = t.valueBuilder(t.params[last], sb)
+}
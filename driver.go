@@ -0,0 +1,57 @@
+package fiqlsqladapter
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// driverDialects maps driver type names to dialect names
+var driverDialects = map[string]string{
+	"*pq.Driver":            DialectPostgres,
+	"*stdlib.Driver":        DialectPostgres,
+	"*mysql.MySQLDriver":    DialectMySQL,
+	"*sqlite3.SQLiteDriver": DialectSQLite,
+	"*sqlite.Driver":        DialectSQLite,
+	"*mssql.Driver":         DialectMSSL,
+	"*godror.drv":           DialectOracle,
+	"*duckdb.Driver":        DialectDuckDB,
+}
+
+// RegisterDriver registers the dialect name for a driver type name
+// as printed by %T e.g. *pq.Driver
+func RegisterDriver(typeName, dialect string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	driverDialects[typeName] = dialect
+}
+
+// DialectForDriver returns the dialect name for the given driver
+// which is recognised by its type name
+func DialectForDriver(d driver.Driver) (string, error) {
+	typeName := fmt.Sprintf("%T", d)
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	if dialect, ok := driverDialects[typeName]; ok {
+		return dialect, nil
+	}
+	if !strings.HasPrefix(typeName, "*") {
+		if dialect, ok := driverDialects["*"+typeName]; ok {
+			return dialect, nil
+		}
+	}
+	return "", newError(ErrorKindUnknownDialect, typeName, nil)
+}
+
+// WithDialectFromDB configures the dialect matching the driver of the given database,
+// an unknown driver makes the adapter return an error on use
+func WithDialectFromDB(db *sql.DB) func(*Adapter) {
+	dialect, err := DialectForDriver(db.Driver())
+	if err != nil {
+		return func(a *Adapter) {
+			a.sqlDialect.err = err
+		}
+	}
+	return WithDialect(dialect)
+}
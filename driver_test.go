@@ -0,0 +1,65 @@
+package fiqlsqladapter
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+type unknownDriver struct{}
+
+func (unknownDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("fiql-fake", &fakeDriver{})
+	sql.Register("fiql-unknown", unknownDriver{})
+}
+
+func unregisterDriver(typeName string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	delete(driverDialects, typeName)
+}
+
+func TestDialectForDriver(t *testing.T) {
+	RegisterDriver("*fiqlsqladapter.fakeDriver", DialectMSSL)
+	t.Cleanup(func() { unregisterDriver("*fiqlsqladapter.fakeDriver") })
+	d, err := DialectForDriver(&fakeDriver{})
+	assert.NoError(t, err)
+	assert.Equal(t, DialectMSSL, d)
+	d, err = DialectForDriver(fakeDriver{})
+	assert.NoError(t, err)
+	assert.Equal(t, DialectMSSL, d)
+	_, err = DialectForDriver(unknownDriver{})
+	assert.EqualError(t, err, "unknown dialect: fiqlsqladapter.unknownDriver")
+}
+
+func TestWithDialectFromDB(t *testing.T) {
+	RegisterDriver("*fiqlsqladapter.fakeDriver", DialectMSSL)
+	t.Cleanup(func() { unregisterDriver("*fiqlsqladapter.fakeDriver") })
+	db, err := sql.Open("fiql-fake", "")
+	assert.NoError(t, err)
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectFromDB(db))
+	res, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `([ID] = @1)`, res.Sql())
+
+	db, err = sql.Open("fiql-unknown", "")
+	assert.NoError(t, err)
+	_, err = NewAdapterFor(&myFunnyRowStruct{}, WithDialectFromDB(db)).Where("id==1")
+	assert.Error(t, err)
+}
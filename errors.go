@@ -0,0 +1,230 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorKind classifies the errors returned by the adapter
+// so they can be rendered for end users
+type ErrorKind string
+
+// ErrorKindUnknown is used for errors not originating from the adapter
+const ErrorKindUnknown ErrorKind = "unknown"
+
+// ErrorKindSyntax indicates that the fiql query could not be parsed
+const ErrorKindSyntax ErrorKind = "syntax"
+
+// ErrorKindInvalidSelector indicates an unknown selector
+const ErrorKindInvalidSelector ErrorKind = "invalid_selector"
+
+// ErrorKindInvalidArgument indicates an argument not matching the fields type
+const ErrorKindInvalidArgument ErrorKind = "invalid_argument"
+
+// ErrorKindInvalidOrderBy indicates an unknown or malformed order by selector
+const ErrorKindInvalidOrderBy ErrorKind = "invalid_order_by"
+
+// ErrorKindNotFilterable indicates a selector which may not be used for filtering
+const ErrorKindNotFilterable ErrorKind = "not_filterable"
+
+// ErrorKindNotSortable indicates a selector which may not be used for sorting
+const ErrorKindNotSortable ErrorKind = "not_sortable"
+
+// ErrorKindComparisonNotAllowed indicates a comparison the field does not allow
+const ErrorKindComparisonNotAllowed ErrorKind = "comparison_not_allowed"
+
+// ErrorKindUnknownComparison indicates a mapping allowing a comparison which does not exist
+const ErrorKindUnknownComparison ErrorKind = "unknown_comparison"
+
+// ErrorKindWildcardNotAllowed indicates a wildcard the field does not allow
+const ErrorKindWildcardNotAllowed ErrorKind = "wildcard_not_allowed"
+
+// ErrorKindWildcardTooShort indicates a wildcard argument with too few literal characters
+const ErrorKindWildcardTooShort ErrorKind = "wildcard_too_short"
+
+// ErrorKindInvalidAggregate indicates an unknown aggregate function,
+// a malformed aggregate or a function not allowed for the type of the field
+const ErrorKindInvalidAggregate ErrorKind = "invalid_aggregate"
+
+// ErrorKindInvalidCursor indicates a cursor which is malformed, tampered with
+// or does not match the order by clause
+const ErrorKindInvalidCursor ErrorKind = "invalid_cursor"
+
+// ErrorKindInvalidPage indicates a page size or offset which is not a non negative number
+const ErrorKindInvalidPage ErrorKind = "invalid_page"
+
+// ErrorKindUnknownDialect indicates a dialect name which is not registered
+const ErrorKindUnknownDialect ErrorKind = "unknown_dialect"
+
+// ErrorKindUnsupportedByDialect indicates a predicate which can not be rebound
+// to a dialect as it uses a construct the dialect renders differently
+const ErrorKindUnsupportedByDialect ErrorKind = "unsupported_by_dialect"
+
+// ErrorKindConfiguration indicates an adapter missing a setting
+// the requested operation needs, the value names the setting
+const ErrorKindConfiguration ErrorKind = "configuration"
+
+// ErrorKindQueryTooLong indicates the raw query exceeds the configured length
+const ErrorKindQueryTooLong ErrorKind = "query_too_long"
+
+// ErrorKindQueryTooDeep indicates the query exceeds the configured nesting depth
+const ErrorKindQueryTooDeep ErrorKind = "query_too_deep"
+
+// ErrorKindTooManyComparisons indicates the query exceeds the configured number of comparisons
+const ErrorKindTooManyComparisons ErrorKind = "too_many_comparisons"
+
+// ErrorKindTooManyParameters indicates the query exceeds the configured number of parameters
+const ErrorKindTooManyParameters ErrorKind = "too_many_parameters"
+
+// Error is the error returned by the adapter
+// Error() is meant for developers, use Localize to
+// render a message for end users
+type Error struct {
+	Kind  ErrorKind
+	Value string
+	err   error
+}
+
+func newError(kind ErrorKind, value string, err error) *Error {
+	return &Error{Kind: kind, Value: value, err: err}
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrorKindInvalidSelector:
+		return "invalid selector: " + e.Value
+	case ErrorKindInvalidArgument:
+		return "invalid type of argument: " + e.Value
+	case ErrorKindInvalidOrderBy:
+		return "invalid order by selector"
+	case ErrorKindNotFilterable:
+		return "selector not filterable: " + e.Value
+	case ErrorKindNotSortable:
+		return "selector not sortable: " + e.Value
+	case ErrorKindComparisonNotAllowed:
+		return "comparison not allowed on selector: " + e.Value
+	case ErrorKindUnknownComparison:
+		return "unknown comparison in mapping: " + e.Value
+	case ErrorKindWildcardNotAllowed:
+		return "wildcard not allowed on selector: " + e.Value
+	case ErrorKindWildcardTooShort:
+		return "wildcard requires at least " + e.Value + " characters"
+	case ErrorKindInvalidAggregate:
+		return "invalid aggregate: " + e.Value
+	case ErrorKindInvalidCursor:
+		return "invalid cursor"
+	case ErrorKindInvalidPage:
+		return "invalid page: " + e.Value
+	case ErrorKindUnknownDialect:
+		return "unknown dialect: " + e.Value
+	case ErrorKindUnsupportedByDialect:
+		return "not supported by dialect: " + e.Value
+	case ErrorKindConfiguration:
+		return "adapter not configured: " + e.Value
+	case ErrorKindQueryTooLong:
+		return "query exceeds maximum length of " + e.Value
+	case ErrorKindQueryTooDeep:
+		return "query exceeds maximum depth of " + e.Value
+	case ErrorKindTooManyComparisons:
+		return "query exceeds maximum number of comparisons of " + e.Value
+	case ErrorKindTooManyParameters:
+		return "query exceeds maximum number of parameters of " + e.Value
+	}
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return string(e.Kind) + ": " + e.Value
+}
+
+// Unwrap returns the underlying error if there is any
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Localize renders the error with the given catalogue
+// if no catalogue is supplied english is used
+func (e *Error) Localize(catalogue MessageCatalogue) string {
+	if catalogue == nil {
+		catalogue = EnglishMessages
+	}
+	return catalogue.Message(e.Kind, e.Value)
+}
+
+// Localize renders any error with the given catalogue
+// errors not originating from the adapter are rendered as ErrorKindUnknown
+func Localize(err error, catalogue MessageCatalogue) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Localize(catalogue)
+	}
+	return newError(ErrorKindUnknown, "", err).Localize(catalogue)
+}
+
+// MessageCatalogue provides user facing messages for error kinds
+type MessageCatalogue interface {
+	Message(kind ErrorKind, value string) string
+}
+
+// Messages is a simple map based MessageCatalogue,
+// {value} is replaced with the offending value
+type Messages map[ErrorKind]string
+
+// Message returns the message for the given kind
+// and falls back to ErrorKindUnknown
+func (m Messages) Message(kind ErrorKind, value string) string {
+	msg, ok := m[kind]
+	if !ok {
+		msg = m[ErrorKindUnknown]
+	}
+	return strings.ReplaceAll(msg, "{value}", value)
+}
+
+// EnglishMessages is the default english catalogue
+var EnglishMessages = Messages{
+	ErrorKindUnknown:              "The filter could not be processed.",
+	ErrorKindSyntax:               "The filter is not well-formed.",
+	ErrorKindInvalidSelector:      "Filtering by '{value}' is not supported.",
+	ErrorKindInvalidArgument:      "The value '{value}' is not valid for this field.",
+	ErrorKindInvalidOrderBy:       "The sort order is not valid.",
+	ErrorKindNotFilterable:        "Filtering by '{value}' is not allowed.",
+	ErrorKindNotSortable:          "Sorting by '{value}' is not allowed.",
+	ErrorKindComparisonNotAllowed: "This comparison is not allowed for '{value}'.",
+	ErrorKindUnknownComparison:    "The comparison '{value}' is not supported.",
+	ErrorKindWildcardNotAllowed:   "Wildcards are not allowed for '{value}'.",
+	ErrorKindWildcardTooShort:     "A search with wildcards requires at least {value} characters.",
+	ErrorKindInvalidAggregate:     "The aggregate '{value}' is not supported.",
+	ErrorKindInvalidCursor:        "The page cursor is not valid.",
+	ErrorKindInvalidPage:          "The page '{value}' is not valid.",
+	ErrorKindUnknownDialect:       "The database dialect '{value}' is not supported.",
+	ErrorKindUnsupportedByDialect: "The filter uses {value} which the database does not support.",
+	ErrorKindConfiguration:        "The request could not be processed.",
+	ErrorKindQueryTooLong:         "The filter may not be longer than {value} characters.",
+	ErrorKindQueryTooDeep:         "The filter may not be nested deeper than {value} levels.",
+	ErrorKindTooManyComparisons:   "The filter may not contain more than {value} conditions.",
+	ErrorKindTooManyParameters:    "The filter may not contain more than {value} values.",
+}
+
+// GermanMessages is the german catalogue
+var GermanMessages = Messages{
+	ErrorKindUnknown:              "Der Filter konnte nicht verarbeitet werden.",
+	ErrorKindSyntax:               "Der Filter ist fehlerhaft.",
+	ErrorKindInvalidSelector:      "Nach '{value}' kann nicht gefiltert werden.",
+	ErrorKindInvalidArgument:      "Der Wert '{value}' ist für dieses Feld ungültig.",
+	ErrorKindInvalidOrderBy:       "Die Sortierung ist ungültig.",
+	ErrorKindNotFilterable:        "Nach '{value}' darf nicht gefiltert werden.",
+	ErrorKindNotSortable:          "Nach '{value}' darf nicht sortiert werden.",
+	ErrorKindComparisonNotAllowed: "Dieser Vergleich ist für '{value}' nicht erlaubt.",
+	ErrorKindUnknownComparison:    "Der Vergleich '{value}' wird nicht unterstützt.",
+	ErrorKindWildcardNotAllowed:   "Platzhalter sind für '{value}' nicht erlaubt.",
+	ErrorKindWildcardTooShort:     "Eine Suche mit Platzhaltern erfordert mindestens {value} Zeichen.",
+	ErrorKindInvalidAggregate:     "Die Aggregation '{value}' wird nicht unterstützt.",
+	ErrorKindInvalidCursor:        "Der Seitenzeiger ist ungültig.",
+	ErrorKindInvalidPage:          "Die Seite '{value}' ist ungültig.",
+	ErrorKindUnknownDialect:       "Der Datenbankdialekt '{value}' wird nicht unterstützt.",
+	ErrorKindUnsupportedByDialect: "Der Filter verwendet {value}, was von der Datenbank nicht unterstützt wird.",
+	ErrorKindConfiguration:        "Die Anfrage konnte nicht verarbeitet werden.",
+	ErrorKindQueryTooLong:         "Der Filter darf nicht länger als {value} Zeichen sein.",
+	ErrorKindQueryTooDeep:         "Der Filter darf nicht tiefer als {value} Ebenen verschachtelt sein.",
+	ErrorKindTooManyComparisons:   "Der Filter darf nicht mehr als {value} Bedingungen enthalten.",
+	ErrorKindTooManyParameters:    "Der Filter darf nicht mehr als {value} Werte enthalten.",
+}
@@ -0,0 +1,58 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorInvalidSelectorKind(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.Where("secret==1")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, ErrorKindInvalidSelector, e.Kind)
+	assert.Equal(t, "secret", e.Value)
+	assert.Equal(t, "invalid selector: secret", err.Error())
+}
+
+func TestErrorLocalizeGerman(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.Where("id==abc")
+	assert.Equal(t, "Der Wert 'abc' ist für dieses Feld ungültig.", Localize(err, GermanMessages))
+}
+
+func TestErrorLocalizeDefaultsToEnglish(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.OrderBy("-nope")
+	assert.Equal(t, "The sort order is not valid.", Localize(err, nil))
+}
+
+func TestErrorLocalizeSyntax(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.Where("id=x=1")
+	assert.Equal(t, "The filter is not well-formed.", Localize(err, EnglishMessages))
+}
+
+func TestErrorLocalizeForeignError(t *testing.T) {
+	assert.Equal(t, "The filter could not be processed.", Localize(errors.New("boom"), EnglishMessages))
+}
+
+func TestErrorLocalizeWildcards(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithWildcardPolicy(WildcardsForbidden))
+	_, err := adp.Where("tx==ab*")
+	assert.Equal(t, "Wildcards are not allowed for 'tx'.", Localize(err, EnglishMessages))
+	assert.Equal(t, "Platzhalter sind für 'tx' nicht erlaubt.", Localize(err, GermanMessages))
+
+	adp = NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithMinWildcardLength(3))
+	_, err = adp.Where("tx==a*")
+	assert.Equal(t, "A search with wildcards requires at least 3 characters.", Localize(err, EnglishMessages))
+	assert.Equal(t, "Eine Suche mit Platzhaltern erfordert mindestens 3 Zeichen.", Localize(err, GermanMessages))
+}
+
+func TestErrorLocalizeUnknownDialect(t *testing.T) {
+	_, err := NewAdapterFor(&myFunnyRowStruct{}, WithDialect("nope")).Where("id==1")
+	assert.Equal(t, "The database dialect 'nope' is not supported.", Localize(err, EnglishMessages))
+	assert.Equal(t, "Der Datenbankdialekt 'nope' wird nicht unterstützt.", Localize(err, GermanMessages))
+}
@@ -0,0 +1,95 @@
+package fiqlsqladapter
+
+import "strings"
+
+// existsSpan is a comparison or an expression of the predicate
+// which may be wrapped into an EXISTS subquery
+type existsSpan struct {
+	start, end int
+	// relation is the one-to-many relation all comparisons
+	// of the span refer to, empty if there is none or they differ
+	relation string
+}
+
+// existsGroup tracks the spans of an expression while it is visited
+type existsGroup struct {
+	start    int
+	children []existsSpan
+}
+
+func (t *whereBuilder) enterExistsGroup() {
+	t.groups = append(t.groups, existsGroup{start: t.sb.Len()})
+}
+
+// addExistsSpan starts a comparison of the current expression
+func (t *whereBuilder) addExistsSpan(relation string) {
+	if len(t.groups) == 0 {
+		return
+	}
+	g := &t.groups[len(t.groups)-1]
+	g.children = append(g.children, existsSpan{start: t.sb.Len(), relation: relation})
+}
+
+// endExistsSpan ends the last comparison or expression of the current expression
+func (t *whereBuilder) endExistsSpan() {
+	if len(t.groups) == 0 {
+		return
+	}
+	g := &t.groups[len(t.groups)-1]
+	if len(g.children) > 0 {
+		g.children[len(g.children)-1].end = t.sb.Len()
+	}
+}
+
+// leaveExistsGroup is called before the closing brace of an expression is written,
+// every related comparison gets its own subquery, only an expression the user
+// put in parentheses whose comparisons all refer to the same one-to-many relation
+// is handed to its parent as a whole so it ends up in a single subquery
+func (t *whereBuilder) leaveExistsGroup() {
+	t.endExistsSpan()
+	g := t.groups[len(t.groups)-1]
+	t.groups = t.groups[:len(t.groups)-1]
+	// the outer most expression is implicit and never shares a subquery
+	relation := ""
+	if len(t.groups) > 0 {
+		relation = uniformRelation(g.children)
+	}
+	if relation == "" {
+		for i := len(g.children) - 1; i >= 0; i-- {
+			if g.children[i].relation != "" {
+				t.wrapExists(g.children[i])
+			}
+		}
+	}
+	t.sb.WriteString(")")
+	if len(t.groups) > 0 {
+		parent := &t.groups[len(t.groups)-1]
+		parent.children = append(parent.children, existsSpan{start: g.start, end: t.sb.Len(), relation: relation})
+	}
+}
+
+func uniformRelation(spans []existsSpan) string {
+	if len(spans) == 0 {
+		return ""
+	}
+	for _, s := range spans[1:] {
+		if s.relation != spans[0].relation {
+			return ""
+		}
+	}
+	return spans[0].relation
+}
+
+// wrapExists wraps the span into the EXISTS subquery of its relation,
+// placeholders are abstract so no parameter has to be renumbered
+func (t *whereBuilder) wrapExists(s existsSpan) {
+	sql := t.sb.String()
+	var sb strings.Builder
+	sb.WriteString(sql[:s.start])
+	sb.WriteString(t.exists[s.relation])
+	sb.WriteString(sql[s.start:s.end])
+	sb.WriteString(")")
+	sb.WriteString(sql[s.end:])
+	t.sb.Reset()
+	t.sb.WriteString(sb.String())
+}
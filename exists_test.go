@@ -0,0 +1,134 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type itemRowStruct struct {
+	Sku      string `fiql:"sku,db:sku"`
+	Quantity int    `fiql:"qty,db:quantity"`
+}
+
+func itemsRelation() func(*Adapter) {
+	return WithRelation(Relation{
+		Name:       "items",
+		Table:      "order_items",
+		LocalKey:   "id",
+		ForeignKey: "order_id",
+		Fields:     MappingFor(&itemRowStruct{}),
+		Many:       true,
+	})
+}
+
+func TestExistsSingleComparison(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	res, err := adp.Where("items.sku==ABC")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."sku" LIKE $1))`, s)
+	assert.Equal(t, []interface{}{"ABC"}, args)
+	assert.Equal(t, "", res.Joins())
+}
+
+func TestExistsGroupsShareSubquery(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"), itemsRelation(), WithScope("tenant_id", 7))
+	res, err := adp.Where("id=gt=10;(items.sku==A*,items.qty=ge=5)")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(([orders].[ID] > @1 AND EXISTS (SELECT 1 FROM [order_items] [items] WHERE [items].[order_id] = [orders].[id] AND ([items].[sku] LIKE CONCAT(@2,'%') OR [items].[quantity] >= @3))) AND [orders].[tenant_id] = @4)`, s)
+	assert.Equal(t, []interface{}{10, "A", 5, 7}, args)
+}
+
+func TestExistsMixedGroupWrapsEachComparison(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	res, err := adp.Where("items.sku==A;id==1,items.qty=lt=2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."sku" LIKE $1) AND "orders"."ID" = $2 OR EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."quantity" < $3))`, res.Sql())
+}
+
+func TestExistsRestrictions(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	_, err := adp.OrderBy("items.sku")
+	assert.Error(t, err)
+	_, err = adp.Select("id,items.sku")
+	assert.Error(t, err)
+	_, err = NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), itemsRelation()).Where("items.sku==A")
+	assert.Error(t, err)
+}
+
+func TestExistsNestedGroupSharesSubquery(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	res, err := adp.Where("(items.sku==A;(items.qty=gt=1,items.qty=lt=0))")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND ("items"."sku" LIKE $1 AND ("items"."quantity" > $2 OR "items"."quantity" < $3))))`, res.Sql())
+
+	res, err = adp.Where("items.sku==A;(items.qty=gt=1,items.qty=lt=0)")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."sku" LIKE $1) AND EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND ("items"."quantity" > $2 OR "items"."quantity" < $3)))`, res.Sql())
+}
+
+func TestExistsIndependentOfSiblings(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	res, err := adp.Where("items.sku==A;items.qty==2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."sku" LIKE $1) AND EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."quantity" = $2))`, res.Sql())
+
+	res, err = adp.Where("items.sku==A;items.qty==2;id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."sku" LIKE $1) AND EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND "items"."quantity" = $2) AND "orders"."ID" = $3)`, res.Sql())
+}
+
+func TestExistsParenthesesShareSubquery(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), itemsRelation())
+	res, err := adp.Where("(items.sku==A;items.qty==2)")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND ("items"."sku" LIKE $1 AND "items"."quantity" = $2)))`, res.Sql())
+
+	res, err = adp.Where("(items.sku==A;items.qty==2);id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(EXISTS (SELECT 1 FROM "order_items" "items" WHERE "items"."order_id" = "orders"."id" AND ("items"."sku" LIKE $1 AND "items"."quantity" = $2)) AND "orders"."ID" = $3)`, res.Sql())
+}
+
+func TestExistsRequiresTableName(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), itemsRelation())
+	_, err := adp.Where("items.sku==A;items.qty=gt=1")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindConfiguration, e.Kind)
+	assert.Equal(t, "adapter not configured: table name", err.Error())
+}
@@ -0,0 +1,59 @@
+package fiqlsqladapter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func expressionMapping() FieldMapping {
+	return NewMappingBuilder().
+		AddIntMapping("id", "id").
+		AddExpressionMapping(`{amount} - {fee}`, "net", reflect.TypeOf(float64(0))).
+		AddExpressionMapping(`LOWER({email})`, "email", reflect.TypeOf("")).
+		Build()
+}
+
+func TestExpressionWhere(t *testing.T) {
+	adp := NewAdapter(expressionMapping(), WithDialectPostgres(), WithTableName("orders"))
+	res, err := adp.Where("net=gt=10;email==jo*")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(("amount" - "fee") > $1 AND (LOWER("email")) LIKE CONCAT($2,'%'))`, s)
+	assert.Equal(t, []interface{}{10.0, "jo"}, args)
+}
+
+func TestExpressionOrderByAndSelect(t *testing.T) {
+	adp := NewAdapter(expressionMapping(), WithDialectMSSQL())
+	ob, err := adp.OrderBy("-net;id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `([amount] - [fee]) DESC, [id] ASC`, ob.Sql())
+	sel, err := adp.Select("id,net")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `[id] AS [id], ([amount] - [fee]) AS [net]`, sel.Sql())
+}
+
+func TestExpressionKeepsPrecedence(t *testing.T) {
+	m := NewMappingBuilder().
+		AddExpressionMapping(`{c.first_name} || ' ' || {c.last_name}`, "name", reflect.TypeOf("")).
+		AddExpressionMapping(`{{"a": 1}`, "doc", reflect.TypeOf("")).
+		Build()
+	adp := NewAdapter(m, WithDialectOracle())
+	res, err := adp.Where("name==Jo*,doc==x")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(("C"."FIRST_NAME" || ' ' || "C"."LAST_NAME") LIKE :1 || '%' OR ({"a": 1}) LIKE :2)`, res.Sql())
+}
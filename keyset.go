@@ -0,0 +1,242 @@
+package fiqlsqladapter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithCursorSecret sets the secret cursor tokens are signed with
+func WithCursorSecret(secret []byte) func(*Adapter) {
+	return func(a *Adapter) {
+		a.cursorSecret = secret
+	}
+}
+
+// Seek generates a keyset pagination predicate which selects the rows
+// after the row with the given sort key values, one value per term of the clause.
+// NULL values are not supported in the sort keys so terms with a NULLS FIRST / NULLS LAST
+// placement are rejected, use a unique tie breaker so the order is deterministic
+func (a *Adapter) Seek(clause *OrderByClause, values ...interface{}) (*WherePredicate, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if err := seekable(clause, len(values)); err != nil {
+		return nil, err
+	}
+	keys := make([]interface{}, len(values))
+	for i, v := range values {
+		k, err := cursorValue(clause.terms[i].field.Type, v)
+		if err != nil {
+			return nil, newError(ErrorKindInvalidCursor, clause.terms[i].field.Alias, err)
+		}
+		keys[i] = k
+	}
+	values = keys
+	p := &WherePredicate{style: a.params(), aliasNames: a.aliasParamNames, delim: a.delim, concat: a.concat}
+	var sb strings.Builder
+	if a.rowValues && sameDirection(clause.terms) {
+		p.usage.rowValues = true
+		a.rowSeekBuilder(p, clause.terms, values, &sb)
+	} else {
+		a.expandedSeekBuilder(p, clause.terms, values, &sb)
+	}
+	p.sql = sb.String()
+	p.usage = p.usage.of(a.sqlDialect)
+	return p, nil
+}
+
+// seekable checks that the clause has one term per value and none of
+// its terms places NULL values as the seek predicate does not handle them
+func seekable(clause *OrderByClause, values int) error {
+	if clause == nil || len(clause.terms) == 0 || len(clause.terms) != values {
+		return newError(ErrorKindInvalidCursor, "", nil)
+	}
+	for _, t := range clause.terms {
+		if t.nulls != NullsDefault {
+			return newError(ErrorKindInvalidCursor, t.field.Alias, nil)
+		}
+	}
+	return nil
+}
+
+func sameDirection(terms []orderTerm) bool {
+	for _, t := range terms[1:] {
+		if t.desc != terms[0].desc {
+			return false
+		}
+	}
+	return true
+}
+
+func seekOperator(t orderTerm) string {
+	if t.desc {
+		return " < "
+	}
+	return " > "
+}
+
+func (a *Adapter) seekValueBuilder(p *WherePredicate, t orderTerm, v interface{}, sb *strings.Builder) {
+	if _, ok := v.(time.Time); ok {
+		p.usage.times = true
+	}
+	p.params = append(p.params, a.valueBuilder(v, sb))
+	p.aliases = append(p.aliases, t.field.Alias)
+}
+
+// rowSeekBuilder writes ((a, b) > (?, ?))
+func (a *Adapter) rowSeekBuilder(p *WherePredicate, terms []orderTerm, values []interface{}, sb *strings.Builder) {
+	sb.WriteString("((")
+	for i, t := range terms {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fieldBuilder(a.tableName, t.field, sb)
+	}
+	sb.WriteString(")")
+	sb.WriteString(seekOperator(terms[0]))
+	sb.WriteString("(")
+	for i, t := range terms {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		a.seekValueBuilder(p, t, values[i], sb)
+	}
+	sb.WriteString("))")
+}
+
+// expandedSeekBuilder writes (a > ? OR (a = ? AND b < ?))
+func (a *Adapter) expandedSeekBuilder(p *WherePredicate, terms []orderTerm, values []interface{}, sb *strings.Builder) {
+	sb.WriteString("(")
+	for i := range terms {
+		if i > 0 {
+			sb.WriteString(" OR (")
+		}
+		for j := 0; j < i; j++ {
+			fieldBuilder(a.tableName, terms[j].field, sb)
+			sb.WriteString(" = ")
+			a.seekValueBuilder(p, terms[j], values[j], sb)
+			sb.WriteString(" AND ")
+		}
+		fieldBuilder(a.tableName, terms[i].field, sb)
+		sb.WriteString(seekOperator(terms[i]))
+		a.seekValueBuilder(p, terms[i], values[i], sb)
+		if i > 0 {
+			sb.WriteString(")")
+		}
+	}
+	sb.WriteString(")")
+}
+
+// Cursor encodes the sort key values of the last row into an opaque token
+// which is signed with the cursor secret and bound to the order by clause
+func (a *Adapter) Cursor(clause *OrderByClause, values ...interface{}) (string, error) {
+	if len(a.cursorSecret) == 0 {
+		return "", newError(ErrorKindConfiguration, "cursor secret", nil)
+	}
+	if err := seekable(clause, len(values)); err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(a.cursorMac(clause, encoded)), nil
+}
+
+// SeekCursor generates a keyset pagination predicate from a cursor token
+// created by Cursor for the same order by clause
+func (a *Adapter) SeekCursor(clause *OrderByClause, token string) (*WherePredicate, error) {
+	values, err := a.decodeCursor(clause, token)
+	if err != nil {
+		return nil, err
+	}
+	return a.Seek(clause, values...)
+}
+
+func (a *Adapter) cursorMac(clause *OrderByClause, payload string) []byte {
+	mac := hmac.New(sha256.New, a.cursorSecret)
+	mac.Write([]byte(clause.sql))
+	mac.Write([]byte{0})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func (a *Adapter) decodeCursor(clause *OrderByClause, token string) ([]interface{}, error) {
+	if len(a.cursorSecret) == 0 {
+		return nil, newError(ErrorKindConfiguration, "cursor secret", nil)
+	}
+	parts := strings.SplitN(token, ".", 2)
+	if clause == nil || len(parts) != 2 {
+		return nil, newError(ErrorKindInvalidCursor, token, nil)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, a.cursorMac(clause, parts[0])) {
+		return nil, newError(ErrorKindInvalidCursor, token, err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, newError(ErrorKindInvalidCursor, token, err)
+	}
+	var raw []interface{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil || len(raw) != len(clause.terms) {
+		return nil, newError(ErrorKindInvalidCursor, token, err)
+	}
+	values := make([]interface{}, len(raw))
+	for i, v := range raw {
+		if values[i], err = cursorValue(clause.terms[i].field.Type, v); err != nil {
+			return nil, newError(ErrorKindInvalidCursor, token, err)
+		}
+	}
+	return values, nil
+}
+
+// cursorValue converts a json decoded value back to the type of the field,
+// a value which already has the type of the field is returned as is
+func cursorValue(t reflect.Type, v interface{}) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		v = rv.Elem().Interface()
+	}
+	if t != nil && v != nil && reflect.TypeOf(v) == t {
+		return v, nil
+	}
+	switch {
+	case t == timeType:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("invalid time value")
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case t == intType:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, errors.New("invalid int value")
+		}
+		return strconv.Atoi(n.String())
+	case t == float64Type:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, errors.New("invalid float value")
+		}
+		return n.Float64()
+	case t == stringType:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, errors.New("invalid string value")
+	}
+	return v, nil
+}
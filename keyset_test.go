@@ -0,0 +1,189 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeekRowValues(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTieBreaker("id"))
+	ob, err := adp.OrderBy("-amt;-id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	res, err := adp.Seek(ob, 10.5, 7)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(("amount", "ID") < ($1, $2))`, s)
+	assert.Equal(t, []interface{}{10.5, 7}, args)
+}
+
+func TestSeekExpandedMixedDirections(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTieBreaker("id"))
+	ob, err := adp.OrderBy("-amt")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	res, err := adp.Seek(ob, 10.5, 7)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("amount" < $1 OR ("amount" = $2 AND "ID" > $3))`, s)
+	assert.Equal(t, []interface{}{10.5, 10.5, 7}, args)
+}
+
+func TestSeekExpandedMSSQL(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL())
+	ob, err := adp.OrderBy("cre;tx;id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	ts := time.Date(2022, 9, 16, 10, 15, 4, 0, time.UTC)
+	res, err := adp.Seek(ob, ts, "a", 1)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `([created_at] > @1 OR ([created_at] = @2 AND [Tx] > @3) OR ([created_at] = @4 AND [Tx] = @5 AND [ID] > @6))`, res.Sql())
+	_, err = adp.Seek(ob, ts)
+	assert.Error(t, err)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithCursorSecret([]byte("s3cret")), WithTieBreaker("id"))
+	ob, err := adp.OrderBy("-cre")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	ts := time.Date(2022, 9, 16, 10, 15, 4, 123000000, time.UTC)
+	token, err := adp.Cursor(ob, ts, 42)
+	assert.NoError(t, err)
+	res, err := adp.SeekCursor(ob, token)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("created_at" < $1 OR ("created_at" = $2 AND "ID" > $3))`, res.Sql())
+	assert.Equal(t, []interface{}{ts, ts, 42}, res.Parameters())
+}
+
+func TestCursorTampered(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithCursorSecret([]byte("s3cret")))
+	ob, err := adp.OrderBy("id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	token, err := adp.Cursor(ob, 42)
+	assert.NoError(t, err)
+	forged := strings.Replace(token, token[:2], "WzQ", 1)
+	_, err = adp.SeekCursor(ob, forged)
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, ErrorKindInvalidCursor, e.Kind)
+
+	other, err := adp.OrderBy("-id")
+	assert.NoError(t, err)
+	_, err = adp.SeekCursor(other, token)
+	assert.Error(t, err)
+
+	_, err = NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithCursorSecret([]byte("other"))).SeekCursor(ob, token)
+	assert.Error(t, err)
+}
+
+func TestCursorRequiresSecret(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	ob, err := adp.OrderBy("id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	_, err = adp.Cursor(ob, 1)
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindConfiguration, e.Kind)
+	assert.Equal(t, "cursor secret", e.Value)
+	_, err = adp.SeekCursor(ob, "a.b")
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, ErrorKindConfiguration, e.Kind)
+}
+
+func TestSeekNilClause(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithCursorSecret([]byte("s3cret")))
+	_, err := adp.Seek(nil, 1)
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindInvalidCursor, e.Kind)
+	_, err = adp.Cursor(nil, 1)
+	assert.Error(t, err)
+	_, err = adp.SeekCursor(nil, "a.b")
+	assert.Error(t, err)
+}
+
+func TestSeekRejectsNullsPlacement(t *testing.T) {
+	for _, dialect := range []func(*Adapter){WithDialectPostgres(), WithDialectMSSQL(), WithDialectMariaDB()} {
+		adp := NewAdapterFor(&myFunnyRowStruct{}, dialect, WithCursorSecret([]byte("s3cret")))
+		ob, err := adp.OrderBy("-upd!nullslast;id")
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+		_, err = adp.Seek(ob, time.Now(), 1)
+		var e *Error
+		assert.True(t, errors.As(err, &e))
+		if e == nil {
+			return
+		}
+		assert.Equal(t, ErrorKindInvalidCursor, e.Kind)
+		assert.Equal(t, "upd", e.Value)
+		_, err = adp.Cursor(ob, time.Now(), 1)
+		assert.Error(t, err)
+	}
+}
+
+func TestSeekValueTypes(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	ob, err := adp.OrderBy("cre;id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	_, err = adp.Seek(ob, "not a time", 1)
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindInvalidCursor, e.Kind)
+	assert.Equal(t, "cre", e.Value)
+	_, err = adp.Seek(ob, time.Now(), "1")
+	assert.Error(t, err)
+	ts := time.Date(2022, 9, 16, 10, 15, 4, 0, time.UTC)
+	res, err := adp.Seek(ob, &ts, 1)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, []interface{}{ts, 1}, res.Parameters())
+}
@@ -0,0 +1,49 @@
+package fiqlsqladapter
+
+import "strconv"
+
+// queryLimits caps the complexity of a query, zero means unlimited
+type queryLimits struct {
+	length      int
+	depth       int
+	comparisons int
+	params      int
+}
+
+// WithMaxQueryLength limits the length of the raw fiql query
+func WithMaxQueryLength(n int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.limits.length = n
+	}
+}
+
+// WithMaxDepth limits the nesting depth of a query,
+// the outer most expression counts as depth one
+func WithMaxDepth(n int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.limits.depth = n
+	}
+}
+
+// WithMaxComparisons limits the number of comparisons in a query,
+// unary selectors count as comparison as well
+func WithMaxComparisons(n int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.limits.comparisons = n
+	}
+}
+
+// WithMaxParameters limits the number of bound parameters a query may produce
+func WithMaxParameters(n int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.limits.params = n
+	}
+}
+
+func exceeds(limit, value int) bool {
+	return limit > 0 && value > limit
+}
+
+func limitError(kind ErrorKind, limit int) *Error {
+	return newError(kind, strconv.Itoa(limit), nil)
+}
@@ -0,0 +1,56 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func limitKind(t *testing.T, err error) ErrorKind {
+	var e *Error
+	if !assert.True(t, errors.As(err, &e)) {
+		return ""
+	}
+	return e.Kind
+}
+
+func TestMaxQueryLength(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithMaxQueryLength(5))
+	_, err := adp.Where("tx==123456")
+	assert.Equal(t, ErrorKindQueryTooLong, limitKind(t, err))
+	assert.Equal(t, "query exceeds maximum length of 5", err.Error())
+	_, err = adp.Where("id==1")
+	assert.NoError(t, err)
+}
+
+func TestMaxDepth(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithMaxDepth(2))
+	_, err := adp.Where("id==1;(id==2,(id==3;id==4))")
+	assert.Equal(t, ErrorKindQueryTooDeep, limitKind(t, err))
+	_, err = adp.Where("id==1;(id==2,id==3)")
+	assert.NoError(t, err)
+}
+
+func TestMaxComparisons(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithMaxComparisons(2))
+	_, err := adp.Where("id==1;id==2;tx")
+	assert.Equal(t, ErrorKindTooManyComparisons, limitKind(t, err))
+	_, err = adp.Where("id==1;tx")
+	assert.NoError(t, err)
+}
+
+func TestMaxParameters(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithMaxParameters(2))
+	_, err := adp.Where("id==1,id==2,id==3")
+	assert.Equal(t, ErrorKindTooManyParameters, limitKind(t, err))
+	res, err := adp.Where("id==1,id==2;tx")
+	assert.NoError(t, err)
+	assert.Len(t, res.Parameters(), 2)
+}
+
+func TestLimitErrorTakesPrecedence(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithMaxComparisons(1))
+	_, err := adp.Where("id==1;nope==2")
+	assert.Equal(t, ErrorKindTooManyComparisons, limitKind(t, err))
+}
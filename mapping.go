@@ -2,6 +2,7 @@ package fiqlsqladapter
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -31,12 +32,99 @@ func isPointerCompatibleType(actual reflect.Type, expected reflect.Type) bool {
 	return false
 }
 
+// Comparison names a fiql comparison, used to restrict
+// the comparisons allowed on a field
+type Comparison string
+
+// ComparisonEq is the == comparison
+const ComparisonEq Comparison = "eq"
+
+// ComparisonNe is the != comparison
+const ComparisonNe Comparison = "ne"
+
+// ComparisonGt is the =gt= comparison
+const ComparisonGt Comparison = "gt"
+
+// ComparisonGe is the =ge= comparison
+const ComparisonGe Comparison = "ge"
+
+// ComparisonLt is the =lt= comparison
+const ComparisonLt Comparison = "lt"
+
+// ComparisonLe is the =le= comparison
+const ComparisonLe Comparison = "le"
+
+// comparisons are all known comparisons
+var comparisons = []Comparison{ComparisonEq, ComparisonNe, ComparisonGt, ComparisonGe, ComparisonLt, ComparisonLe}
+
+// validateMapping checks that every field only allows known comparisons
+func validateMapping(m FieldMapping) error {
+	for _, f := range m {
+		for _, c := range f.Comparisons {
+			known := false
+			for _, k := range comparisons {
+				if c == k {
+					known = true
+					break
+				}
+			}
+			if !known {
+				return newError(ErrorKindUnknownComparison, string(c), nil)
+			}
+		}
+	}
+	return nil
+}
+
 // Field is a fiql field to database column mapping
 type Field struct {
 	Db          string
 	Alias       string
 	Type        reflect.Type
 	TablePrefix string
+	// Comparisons restricts the allowed comparisons, empty allows all
+	Comparisons []Comparison
+	// Wildcards restricts the usage of wildcards in arguments
+	Wildcards WildcardPolicy
+	// MinWildcardLength is the minimum literal length of an argument using a wildcard
+	MinWildcardLength int
+	// Roles restricts the field to callers having any of the roles, empty allows everyone
+	Roles []string
+	// Nulls is the default placement of NULL values when sorting by the field
+	Nulls NullsOrder
+	// NoFilter prevents the field from being used in where predicates
+	NoFilter bool
+	// NoSort prevents the field from being used in order by clauses
+	NoSort bool
+	// Expression is a trusted sql expression used instead of the column
+	// e.g. {amount} - {fee}, {name} is delimited by the dialect,
+	// everything else is written as is
+	Expression string
+	// relation is the name of the relation the field belongs to
+	relation string
+}
+
+// Filterable returns true if the field may be used in where predicates
+func (f Field) Filterable() bool {
+	return !f.NoFilter
+}
+
+// Sortable returns true if the field may be used in order by clauses
+func (f Field) Sortable() bool {
+	return !f.NoSort
+}
+
+// Allows returns true if the comparison may be used on the field
+func (f Field) Allows(c Comparison) bool {
+	if len(f.Comparisons) == 0 {
+		return true
+	}
+	for _, v := range f.Comparisons {
+		if v == c {
+			return true
+		}
+	}
+	return false
 }
 
 // FieldMapping is a table mapping of fields
@@ -94,6 +182,76 @@ func (b *MappingBuilder) AddIntMapping(column, selector string) *MappingBuilder
 	return b
 }
 
+// AddExpressionMapping adds a trusted sql expression to fiql selector mapping
+// e.g. AddExpressionMapping("LOWER({email})", "email", reflect.TypeOf("")),
+// {name} is delimited by the dialect, {{ is a literal {, everything else
+// is written as is so the expression must never contain user input
+func (b *MappingBuilder) AddExpressionMapping(expression, selector string, resultType reflect.Type) *MappingBuilder {
+	b.fm[strings.ToLower(selector)] = Field{
+		Alias:      selector,
+		Type:       resultType,
+		Expression: expression,
+	}
+	return b
+}
+
+// AllowComparisons restricts an already added selector to the given comparisons,
+// an adapter using a mapping with unknown comparisons reports ErrorKindUnknownComparison
+func (b *MappingBuilder) AllowComparisons(selector string, comparisons ...Comparison) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.Comparisons = comparisons
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
+// RestrictWildcards sets the wildcard policy and the minimum literal length
+// of arguments using wildcards for an already added selector
+func (b *MappingBuilder) RestrictWildcards(selector string, policy WildcardPolicy, minLength int) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.Wildcards = policy
+		f.MinWildcardLength = minLength
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
+// RequireRoles restricts an already added selector to callers having any of the given roles
+func (b *MappingBuilder) RequireRoles(selector string, roles ...string) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.Roles = roles
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
+// SortNulls sets the default placement of NULL values when sorting by an already added selector
+func (b *MappingBuilder) SortNulls(selector string, nulls NullsOrder) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.Nulls = nulls
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
+// DisableFilter prevents an already added selector from being used in where predicates
+func (b *MappingBuilder) DisableFilter(selector string) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.NoFilter = true
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
+// DisableSort prevents an already added selector from being used in order by clauses
+func (b *MappingBuilder) DisableSort(selector string) *MappingBuilder {
+	if f, ok := b.fm[strings.ToLower(selector)]; ok {
+		f.NoSort = true
+		b.fm[strings.ToLower(selector)] = f
+	}
+	return b
+}
+
 // Build returns the generated
 func (b *MappingBuilder) Build() FieldMapping {
 	return b.fm
@@ -119,6 +277,12 @@ func tagsFromStruct(s interface{}) FieldMapping {
 		alias := parts[0]
 		db := f.Name
 		tablePrefix := ""
+		var comparisons []Comparison
+		wildcards := WildcardsAllowed
+		minWildcardLength := 0
+		var roles []string
+		nulls := NullsDefault
+		noFilter, noSort := false, false
 		if len(parts) > 1 {
 			for _, v := range parts[1:] {
 				if strings.HasPrefix(v, "db:") {
@@ -129,15 +293,45 @@ func tagsFromStruct(s interface{}) FieldMapping {
 						db = splitTablePrefix[1]
 					}
 				}
+				if strings.HasPrefix(v, "ops:") {
+					for _, op := range strings.Split(strings.TrimPrefix(v, "ops:"), "|") {
+						comparisons = append(comparisons, Comparison(strings.ToLower(op)))
+					}
+				}
+				switch {
+				case v == "nowildcard":
+					wildcards = WildcardsForbidden
+				case v == "noleadingwildcard" && wildcards < WildcardsNoLeading:
+					wildcards = WildcardsNoLeading
+				case strings.HasPrefix(v, "minwildcard:"):
+					minWildcardLength, _ = strconv.Atoi(strings.TrimPrefix(v, "minwildcard:"))
+				case strings.HasPrefix(v, "roles:"):
+					roles = strings.Split(strings.TrimPrefix(v, "roles:"), "|")
+				case v == "nullsfirst":
+					nulls = NullsFirst
+				case v == "nullslast":
+					nulls = NullsLast
+				case v == "nofilter":
+					noFilter = true
+				case v == "nosort":
+					noSort = true
+				}
 			}
 		}
 
 		alias = strings.ToLower(alias)
 		m[alias] = Field{
-			Alias:       alias,
-			Type:        f.Type,
-			Db:          db,
-			TablePrefix: tablePrefix,
+			Alias:             alias,
+			Type:              f.Type,
+			Db:                db,
+			TablePrefix:       tablePrefix,
+			Comparisons:       comparisons,
+			Wildcards:         wildcards,
+			MinWildcardLength: minWildcardLength,
+			Roles:             roles,
+			Nulls:             nulls,
+			NoFilter:          noFilter,
+			NoSort:            noSort,
 		}
 	}
 	return m
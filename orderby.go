@@ -0,0 +1,176 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"strings"
+)
+
+// NullsOrder defines where NULL values are placed when sorting
+type NullsOrder int
+
+// NullsDefault leaves the placement of NULL values to the database
+const NullsDefault NullsOrder = 0
+
+// NullsFirst places NULL values before all other values
+const NullsFirst NullsOrder = 1
+
+// NullsLast places NULL values after all other values
+const NullsLast NullsOrder = 2
+
+// orderTerm is a single parsed order by term
+type orderTerm struct {
+	field Field
+	desc  bool
+	nulls NullsOrder
+}
+
+// WithDefaultOrder sets the order used if OrderBy is called with an empty query,
+// it uses the same format as OrderBy but is not subject to permissions
+func WithDefaultOrder(query string) func(*Adapter) {
+	return func(a *Adapter) {
+		a.defaultOrder = query
+	}
+}
+
+// WithTieBreaker sets terms which are appended to every order by clause
+// unless the clause already sorts by them, use a unique column like
+// the primary key to make the order deterministic e.g. WithTieBreaker("id")
+func WithTieBreaker(query string) func(*Adapter) {
+	return func(a *Adapter) {
+		a.tieBreaker = query
+	}
+}
+
+func permitAll(Field) bool {
+	return true
+}
+
+// appendTieBreakers appends the tie breakers not yet contained in terms
+func appendTieBreakers(terms, tieBreakers []orderTerm) []orderTerm {
+	for _, tb := range tieBreakers {
+		contained := false
+		for _, t := range terms {
+			if strings.EqualFold(t.field.Alias, tb.field.Alias) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			terms = append(terms, tb)
+		}
+	}
+	return terms
+}
+
+// OrderBy generates a order by clause from a given query
+// this is no fiql but rather the format ([+|-|])ALIAS[!nullsfirst|!nullslast][;([+|-|])ALIAS[!nullsfirst|!nullslast]]*
+func (a *Adapter) OrderBy(query string) (*OrderByClause, error) {
+	return a.OrderByContext(context.Background(), query)
+}
+
+// OrderByContext generates a order by clause from a given query,
+// selectors the permissions of the context do not allow are treated as unknown
+func (a *Adapter) OrderByContext(ctx context.Context, query string) (*OrderByClause, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	permitted := a.permitted(ctx)
+	if query == "" {
+		query = a.defaultOrder
+		permitted = permitAll
+	}
+	var terms []orderTerm
+	if query != "" {
+		var err error
+		if terms, err = a.parseOrderBy(query, permitted); err != nil {
+			return nil, err
+		}
+	}
+	if a.tieBreaker != "" {
+		tieBreakers, err := a.parseOrderBy(a.tieBreaker, permitAll)
+		if err != nil {
+			return nil, err
+		}
+		terms = appendTieBreakers(terms, tieBreakers)
+	}
+	var sb strings.Builder
+	for i, t := range terms {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		a.orderTermBuilder(t, &sb)
+	}
+	fields := make([]Field, len(terms))
+	for i, t := range terms {
+		fields[i] = t.field
+	}
+	return &OrderByClause{sql: sb.String(), terms: terms, joins: a.joinsFor(fields), delim: a.delim}, nil
+}
+
+func (a *Adapter) parseOrderBy(query string, permitted func(Field) bool) ([]orderTerm, error) {
+	s := strings.Split(query, ";")
+	terms := make([]orderTerm, 0, len(s))
+	for _, v := range s {
+		if len(v) == 0 {
+			return nil, newError(ErrorKindInvalidOrderBy, v, nil)
+		}
+		fn := v
+		nulls := NullsDefault
+		if i := strings.IndexRune(fn, '!'); i >= 0 {
+			switch strings.ToLower(fn[i+1:]) {
+			case "nullsfirst":
+				nulls = NullsFirst
+			case "nullslast":
+				nulls = NullsLast
+			default:
+				return nil, newError(ErrorKindInvalidOrderBy, v, nil)
+			}
+			fn = fn[:i]
+		}
+		desc := false
+		if len(fn) > 0 && (fn[0] == '-' || fn[0] == '+') {
+			desc = fn[0] == '-'
+			fn = fn[1:]
+		}
+		f, ok := a.fields[strings.ToLower(fn)]
+		if !ok || !permitted(f) {
+			return nil, newError(ErrorKindInvalidOrderBy, v, nil)
+		}
+		if !f.Sortable() {
+			return nil, newError(ErrorKindNotSortable, fn, nil)
+		}
+		if nulls == NullsDefault {
+			nulls = f.Nulls
+		}
+		terms = append(terms, orderTerm{field: f, desc: desc, nulls: nulls})
+	}
+	return terms, nil
+}
+
+// orderTermBuilder writes a single order by term, NULLS FIRST / NULLS LAST
+// is emulated with a CASE expression on dialects not supporting it
+func (a *Adapter) orderTermBuilder(t orderTerm, sb *strings.Builder) {
+	if t.nulls != NullsDefault && !a.nullsOrdering {
+		sb.WriteString("CASE WHEN ")
+		fieldBuilder(a.tableName, t.field, sb)
+		if t.nulls == NullsFirst {
+			sb.WriteString(" IS NULL THEN 0 ELSE 1 END, ")
+		} else {
+			sb.WriteString(" IS NULL THEN 1 ELSE 0 END, ")
+		}
+	}
+	fieldBuilder(a.tableName, t.field, sb)
+	if t.desc {
+		sb.WriteString(" DESC")
+	} else {
+		sb.WriteString(" ASC")
+	}
+	if a.nullsOrdering {
+		switch t.nulls {
+		case NullsFirst:
+			sb.WriteString(" NULLS FIRST")
+		case NullsLast:
+			sb.WriteString(" NULLS LAST")
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package fiqlsqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderByNullsNative(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	res, err := adp.OrderBy("-upd!nullslast;cre!NullsFirst")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"updated_at" DESC NULLS LAST, "created_at" ASC NULLS FIRST`, res.String())
+}
+
+func TestOrderByNullsEmulated(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMariaDB())
+	res, err := adp.OrderBy("-upd!nullslast;+cre!nullsfirst")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "CASE WHEN `updated_at` IS NULL THEN 1 ELSE 0 END, `updated_at` DESC, CASE WHEN `created_at` IS NULL THEN 0 ELSE 1 END, `created_at` ASC", res.String())
+}
+
+func TestOrderByNullsFieldDefault(t *testing.T) {
+	b := NewMappingBuilder().AddDateMapping("updated_at", "upd").SortNulls("upd", NullsLast).Build()
+	adp := NewAdapter(b, WithDialectMSSQL())
+	res, err := adp.OrderBy("upd")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "CASE WHEN [updated_at] IS NULL THEN 1 ELSE 0 END, [updated_at] ASC", res.String())
+	res, err = adp.OrderBy("upd!nullsfirst")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "CASE WHEN [updated_at] IS NULL THEN 0 ELSE 1 END, [updated_at] ASC", res.String())
+}
+
+func TestOrderByNullsInvalid(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.OrderBy("-upd!nullsmiddle")
+	assert.Error(t, err)
+	_, err = adp.OrderBy("-upd;")
+	assert.Error(t, err)
+}
+
+func TestOrderByNotSortable(t *testing.T) {
+	b := NewMappingBuilder().AddStringMapping("note", "note").AddStringMapping("hash", "hash").DisableSort("note").DisableFilter("hash").Build()
+	adp := NewAdapter(b, WithDialectPostgres())
+	_, err := adp.OrderBy("-note")
+	assert.EqualError(t, err, "selector not sortable: note")
+	_, err = adp.Where("note==x")
+	assert.NoError(t, err)
+	_, err = adp.Where("hash==x")
+	assert.EqualError(t, err, "selector not filterable: hash")
+	res, err := adp.OrderBy("hash")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"hash" ASC`, res.String())
+}
+
+func TestOrderByDefaultOrder(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithDefaultOrder("-cre"))
+	res, err := adp.OrderBy("")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"created_at" DESC`, res.String())
+	res, err = adp.OrderBy("amt")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"amount" ASC`, res.String())
+}
+
+func TestOrderByTieBreaker(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTieBreaker("id"))
+	res, err := adp.OrderBy("-amt")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"amount" DESC, "ID" ASC`, res.String())
+	res, err = adp.OrderBy("-id;amt")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"ID" DESC, "amount" ASC`, res.String())
+	res, err = adp.OrderBy("")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"ID" ASC`, res.String())
+}
+
+func TestOrderByInvalidTieBreaker(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTieBreaker("nope"))
+	_, err := adp.OrderBy("amt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package fiqlsqladapter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// limitStyle defines how a database limits the returned rows
+type limitStyle int
+
+// limitOffsetLimit is the LIMIT n OFFSET m clause
+const limitOffsetLimit limitStyle = 0
+
+// offsetFetchNextLimit is the OFFSET m ROWS FETCH NEXT n ROWS ONLY clause used by mssql
+const offsetFetchNextLimit limitStyle = 1
+
+// offsetFetchFirstLimit is the OFFSET m ROWS FETCH FIRST n ROWS ONLY clause used by oracle
+const offsetFetchFirstLimit limitStyle = 2
+
+// Page is a validated page of rows, a zero limit means no limit
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// WithPageSize sets the page size used if no limit is requested
+// and the maximum page size, larger limits are capped to the maximum,
+// zero means no default and no maximum respectively
+func WithPageSize(defaultSize, maxSize int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.pageSize = defaultSize
+		a.maxPageSize = maxSize
+	}
+}
+
+// ParsePage parses the raw limit and offset e.g. from ?limit=&offset=,
+// empty values fall back to the default page size and offset zero
+func (a *Adapter) ParsePage(limit, offset string) (Page, error) {
+	var p Page
+	var err error
+	if p.Limit, err = parsePageValue(limit, a.pageSize); err != nil {
+		return Page{}, err
+	}
+	if p.Offset, err = parsePageValue(offset, 0); err != nil {
+		return Page{}, err
+	}
+	return a.boundPage(p), nil
+}
+
+func parsePageValue(s string, fallback int) (int, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, newError(ErrorKindInvalidPage, s, err)
+	}
+	return n, nil
+}
+
+// boundPage applies the default and maximum page size
+func (a *Adapter) boundPage(p Page) Page {
+	if p.Limit == 0 {
+		p.Limit = a.pageSize
+	}
+	if a.maxPageSize > 0 && (p.Limit == 0 || p.Limit > a.maxPageSize) {
+		p.Limit = a.maxPageSize
+	}
+	return p
+}
+
+// Limit generates the limit clause of the page for the dialect,
+// the page size bounds of the adapter are applied.
+// MSSQL requires an order by clause for OFFSET FETCH
+func (a *Adapter) Limit(p Page) (*LimitClause, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if p.Limit < 0 {
+		return nil, newError(ErrorKindInvalidPage, strconv.Itoa(p.Limit), nil)
+	}
+	if p.Offset < 0 {
+		return nil, newError(ErrorKindInvalidPage, strconv.Itoa(p.Offset), nil)
+	}
+	p = a.boundPage(p)
+	var sb strings.Builder
+	a.limitBuilder(p, &sb)
+	return &LimitClause{sql: sb.String(), page: p}, nil
+}
+
+// Paginate parses the raw limit and offset and generates the limit clause
+func (a *Adapter) Paginate(limit, offset string) (*LimitClause, error) {
+	p, err := a.ParsePage(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return a.Limit(p)
+}
+
+func (a *Adapter) limitBuilder(p Page, sb *strings.Builder) {
+	if a.limit == limitOffsetLimit {
+		if p.Limit > 0 {
+			sb.WriteString("LIMIT ")
+			sb.WriteString(strconv.Itoa(p.Limit))
+		} else if p.Offset > 0 && a.unboundedLimit != "" {
+			// mysql and sqlite do not allow OFFSET without LIMIT
+			sb.WriteString("LIMIT ")
+			sb.WriteString(a.unboundedLimit)
+		}
+		if p.Offset > 0 {
+			if sb.Len() > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString("OFFSET ")
+			sb.WriteString(strconv.Itoa(p.Offset))
+		}
+		return
+	}
+	if p.Limit == 0 && p.Offset == 0 {
+		return
+	}
+	sb.WriteString("OFFSET ")
+	sb.WriteString(strconv.Itoa(p.Offset))
+	sb.WriteString(" ROWS")
+	if p.Limit > 0 {
+		if a.limit == offsetFetchNextLimit {
+			sb.WriteString(" FETCH NEXT ")
+		} else {
+			sb.WriteString(" FETCH FIRST ")
+		}
+		sb.WriteString(strconv.Itoa(p.Limit))
+		sb.WriteString(" ROWS ONLY")
+	}
+}
@@ -0,0 +1,71 @@
+package fiqlsqladapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageDefaults(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithPageSize(20, 100))
+	p, err := adp.ParsePage("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, Page{Limit: 20}, p)
+	p, err = adp.ParsePage("500", "40")
+	assert.NoError(t, err)
+	assert.Equal(t, Page{Limit: 100, Offset: 40}, p)
+}
+
+func TestParsePageInvalid(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	for _, v := range [][2]string{{"abc", ""}, {"-1", ""}, {"10", "x"}, {"", "-5"}} {
+		_, err := adp.ParsePage(v[0], v[1])
+		var e *Error
+		assert.True(t, errors.As(err, &e), v)
+		if e != nil {
+			assert.Equal(t, ErrorKindInvalidPage, e.Kind)
+		}
+	}
+}
+
+func TestLimitDialects(t *testing.T) {
+	tests := []struct {
+		dialect func(*Adapter)
+		page    Page
+		sql     string
+	}{
+		{WithDialectPostgres(), Page{Limit: 10, Offset: 20}, "LIMIT 10 OFFSET 20"},
+		{WithDialectSQLite(), Page{Limit: 10}, "LIMIT 10"},
+		{WithDialectPostgres(), Page{Offset: 20}, "OFFSET 20"},
+		{WithDialectMariaDB(), Page{}, ""},
+		{WithDialectMariaDB(), Page{Offset: 20}, "LIMIT 18446744073709551615 OFFSET 20"},
+		{WithDialectSQLite(), Page{Offset: 20}, "LIMIT -1 OFFSET 20"},
+		{WithDialectMariaDB(), Page{Limit: 5, Offset: 20}, "LIMIT 5 OFFSET 20"},
+		{WithDialectMSSQL(), Page{Limit: 10, Offset: 20}, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{WithDialectMSSQL(), Page{Offset: 20}, "OFFSET 20 ROWS"},
+		{WithDialectOracle(), Page{Limit: 10}, "OFFSET 0 ROWS FETCH FIRST 10 ROWS ONLY"},
+	}
+	for _, tt := range tests {
+		adp := NewAdapterFor(&myFunnyRowStruct{}, tt.dialect)
+		l, err := adp.Limit(tt.page)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, tt.sql, l.Sql())
+	}
+}
+
+func TestPaginateCapsToMaximum(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithPageSize(0, 50))
+	l, err := adp.Paginate("", "100")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "OFFSET 100 ROWS FETCH NEXT 50 ROWS ONLY", l.Sql())
+	assert.Equal(t, Page{Limit: 50, Offset: 100}, l.Page())
+	_, err = adp.Limit(Page{Limit: -1})
+	assert.Error(t, err)
+}
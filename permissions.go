@@ -0,0 +1,70 @@
+package fiqlsqladapter
+
+import "context"
+
+// Permissions describes the caller of a query
+type Permissions interface {
+	// HasRole returns true if the caller has the given role
+	HasRole(role string) bool
+}
+
+// Roles is a simple Permissions implementation
+type Roles []string
+
+// HasRole returns true if the role is contained
+func (r Roles) HasRole(role string) bool {
+	for _, v := range r {
+		if v == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorizer decides if the caller may use the given field,
+// permissions are nil if the context carries none
+type Authorizer func(p Permissions, f Field) bool
+
+// WithAuthorizer replaces the role check of fields with the given callback
+func WithAuthorizer(authorizer Authorizer) func(*Adapter) {
+	return func(a *Adapter) {
+		a.authorizer = authorizer
+	}
+}
+
+type permissionsKey struct{}
+
+// ContextWithPermissions returns a copy of the context carrying the permissions
+// which are used by WhereContext and OrderByContext
+func ContextWithPermissions(ctx context.Context, p Permissions) context.Context {
+	return context.WithValue(ctx, permissionsKey{}, p)
+}
+
+// PermissionsFromContext returns the permissions of the context or nil
+func PermissionsFromContext(ctx context.Context) Permissions {
+	p, _ := ctx.Value(permissionsKey{}).(Permissions)
+	return p
+}
+
+// permitted returns a check whether the caller may use a field,
+// fields requiring roles are never permitted without permissions
+func (a *Adapter) permitted(ctx context.Context) func(Field) bool {
+	p := PermissionsFromContext(ctx)
+	return func(f Field) bool {
+		if a.authorizer != nil {
+			return a.authorizer(p, f)
+		}
+		if len(f.Roles) == 0 {
+			return true
+		}
+		if p == nil {
+			return false
+		}
+		for _, r := range f.Roles {
+			if p.HasRole(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
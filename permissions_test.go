@@ -0,0 +1,57 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type salaryRowStruct struct {
+	Name   string  `fiql:"name,db:name"`
+	Salary float64 `fiql:"salary,db:salary,roles:admin|hr"`
+}
+
+func TestRolesDenyWithoutPermissions(t *testing.T) {
+	adp := NewAdapterFor(&salaryRowStruct{}, WithDialectPostgres())
+	_, err := adp.Where("salary=gt=100")
+	assert.Error(t, err)
+	assert.Equal(t, "invalid selector: salary", err.Error())
+	_, err = adp.OrderBy("-salary")
+	assert.Error(t, err)
+}
+
+func TestRolesAllowWithPermissions(t *testing.T) {
+	adp := NewAdapterFor(&salaryRowStruct{}, WithDialectPostgres())
+	ctx := ContextWithPermissions(context.Background(), Roles{"hr"})
+	res, err := adp.WhereContext(ctx, "name==jo*;salary=gt=100")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("name" LIKE CONCAT($1,'%') AND "salary" > $2)`, res.Sql())
+	ob, err := adp.OrderByContext(ctx, "-salary")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"salary" DESC`, ob.Sql())
+}
+
+func TestRolesDenyOtherRole(t *testing.T) {
+	adp := NewAdapterFor(&salaryRowStruct{}, WithDialectPostgres())
+	ctx := ContextWithPermissions(context.Background(), Roles{"sales"})
+	_, err := adp.WhereContext(ctx, "salary=gt=100")
+	assert.Error(t, err)
+}
+
+func TestAuthorizerCallback(t *testing.T) {
+	b := NewMappingBuilder().AddStringMapping("name", "name").AddFloatMapping("salary", "salary").Build()
+	adp := NewAdapter(b, WithDialectPostgres(), WithAuthorizer(func(p Permissions, f Field) bool {
+		return f.Alias != "salary" || (p != nil && p.HasRole("boss"))
+	}))
+	_, err := adp.Where("salary=gt=100")
+	assert.Error(t, err)
+	_, err = adp.WhereContext(ContextWithPermissions(context.Background(), Roles{"boss"}), "salary=gt=100")
+	assert.NoError(t, err)
+}
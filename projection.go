@@ -0,0 +1,77 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Select generates a column list from a comma separated list of aliases
+// e.g. fields=id,amt,cre, every column is named by its alias,
+// an empty query selects all fields ordered by alias,
+// an error is returned if there is no field to select
+func (a *Adapter) Select(query string) (*SelectClause, error) {
+	return a.SelectContext(context.Background(), query)
+}
+
+// SelectContext generates a column list from a comma separated list of aliases,
+// selectors the permissions of the context do not allow are treated as unknown
+// and are left out if all fields are selected
+func (a *Adapter) SelectContext(ctx context.Context, query string) (*SelectClause, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	fields, err := a.parseSelect(query, a.permitted(ctx))
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	aliases := make([]string, len(fields))
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fieldBuilder(a.tableName, f, &sb)
+		sb.WriteString(" AS ")
+		aliasBuilder(f.Alias, &sb)
+		aliases[i] = f.Alias
+	}
+	return &SelectClause{sql: sb.String(), aliases: aliases, joins: a.joinsFor(fields), delim: a.delim}, nil
+}
+
+func (a *Adapter) parseSelect(query string, permitted func(Field) bool) ([]Field, error) {
+	if strings.TrimSpace(query) == "" {
+		keys := make([]string, 0, len(a.fields))
+		for k, f := range a.fields {
+			if permitted(f) && !a.many(f) {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			// no field may be selected, an empty column list is no valid sql
+			return nil, newError(ErrorKindInvalidSelector, "*", nil)
+		}
+		sort.Strings(keys)
+		fields := make([]Field, len(keys))
+		for i, k := range keys {
+			fields[i] = a.fields[k]
+		}
+		return fields, nil
+	}
+	s := strings.Split(query, ",")
+	fields := make([]Field, 0, len(s))
+	seen := make(map[string]bool, len(s))
+	for _, v := range s {
+		alias := strings.ToLower(strings.TrimSpace(v))
+		f, ok := a.fields[alias]
+		if !ok || !permitted(f) || a.many(f) {
+			return nil, newError(ErrorKindInvalidSelector, v, nil)
+		}
+		if seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
@@ -0,0 +1,75 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFields(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	res, err := adp.Select("id, amt,cre,id")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"ID" AS "id", "amount" AS "amt", "created_at" AS "cre"`, res.Sql())
+	assert.Equal(t, []string{"id", "amt", "cre"}, res.Aliases())
+}
+
+func TestSelectOracleKeepsAliasCase(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectOracle())
+	res, err := adp.Select("cur")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `"CURRENCY" AS "cur"`, res.Sql())
+}
+
+func TestSelectUnknownField(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	_, err := adp.Select("id,password")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindInvalidSelector, e.Kind)
+	assert.Equal(t, "password", e.Value)
+}
+
+func TestSelectPermissions(t *testing.T) {
+	adp := NewAdapterFor(&salaryRowStruct{}, WithDialectPostgres())
+	_, err := adp.Select("name,salary")
+	assert.Error(t, err)
+	res, err := adp.Select("")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.NotContains(t, res.Aliases(), "salary")
+	res, err = adp.SelectContext(ContextWithPermissions(context.Background(), Roles{"hr"}), "name,salary")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, []string{"name", "salary"}, res.Aliases())
+}
+
+func TestSelectNoPermittedField(t *testing.T) {
+	adp := NewAdapterFor(&salaryRowStruct{}, WithDialectPostgres(), WithTableName("staff"), WithAuthorizer(func(Permissions, Field) bool {
+		return false
+	}))
+	_, err := adp.Select("")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindInvalidSelector, e.Kind)
+	_, err = adp.Statement(SelectQuery{})
+	assert.Error(t, err)
+}
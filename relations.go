@@ -0,0 +1,142 @@
+package fiqlsqladapter
+
+import (
+	"strings"
+)
+
+// JoinType is the type of join used for a relation
+type JoinType string
+
+// JoinLeft keeps rows without a related row, it is the default
+const JoinLeft JoinType = "LEFT JOIN"
+
+// JoinInner drops rows without a related row
+const JoinInner JoinType = "INNER JOIN"
+
+// Relation declares a related table which is joined if one of its fields is used,
+// the fields are addressed as name.alias e.g. customer.name
+type Relation struct {
+	// Name is the selector prefix and the alias of the joined table
+	Name string
+	// Table is the related table
+	Table string
+	// LocalKey is the column of the adapters table referencing the related table
+	LocalKey string
+	// ForeignKey is the column of the related table referenced by the local key
+	ForeignKey string
+	// Join is the type of join, JoinLeft if empty
+	Join JoinType
+	// Fields is the mapping of the related table e.g. MappingFor(&customer{})
+	Fields FieldMapping
+	// Many marks a one-to-many relation, its fields are filtered by an
+	// EXISTS subquery per comparison instead of a join and can not be sorted
+	// or selected, comparisons put in parentheses share a single subquery
+	Many bool
+}
+
+// MappingFor returns the field mapping defined by the struct tags of the typeDef argument
+func MappingFor(typeDef interface{}) FieldMapping {
+	return tagsFromStruct(typeDef)
+}
+
+// WithRelation adds the fields of a related table to the adapter,
+// the local key is qualified with the adapters table name so it should be set,
+// it is required for one-to-many relations
+func WithRelation(r Relation) func(*Adapter) {
+	return func(a *Adapter) {
+		if r.Join == "" {
+			r.Join = JoinLeft
+		}
+		fields := make(FieldMapping, len(a.fields)+len(r.Fields))
+		for k, f := range a.fields {
+			fields[k] = f
+		}
+		for k, f := range r.Fields {
+			f.Alias = r.Name + "." + f.Alias
+			f.TablePrefix = r.Name
+			f.relation = r.Name
+			f.NoSort = f.NoSort || r.Many
+			fields[strings.ToLower(r.Name)+"."+k] = f
+		}
+		a.fields = fields
+		if a.relations == nil {
+			a.relations = make(map[string]Relation)
+		}
+		a.relations[r.Name] = r
+	}
+}
+
+// joinsFor renders the joins needed by the fields in order of first use
+func (a *Adapter) joinsFor(fields []Field) []string {
+	var names []string
+	for _, f := range fields {
+		if f.relation != "" && !a.many(f) {
+			names = appendUnique(names, f.relation)
+		}
+	}
+	joins := make([]string, 0, len(names))
+	for _, n := range names {
+		joins = append(joins, a.joinBuilder(a.relations[n]))
+	}
+	return joins
+}
+
+// joinBuilder writes e.g. LEFT JOIN "customers" "customer" ON "customer"."id" = "orders"."customer_id",
+// the table alias is written without AS as oracle does not support it
+func (a *Adapter) joinBuilder(r Relation) string {
+	var sb strings.Builder
+	sb.WriteString(string(r.Join))
+	sb.WriteString(" ")
+	delimitBuilder(r.Table, &sb)
+	sb.WriteString(" ")
+	delimitBuilder(r.Name, &sb)
+	sb.WriteString(" ON ")
+	columnBuilder(r.Name, "", r.ForeignKey, &sb)
+	sb.WriteString(" = ")
+	columnBuilder("", a.tableName, r.LocalKey, &sb)
+	return sb.String()
+}
+
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		contained := false
+		for _, e := range s {
+			if e == v {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+// many returns true if the field belongs to a one-to-many relation
+func (a *Adapter) many(f Field) bool {
+	return f.relation != "" && a.relations[f.relation].Many
+}
+
+// existsPrefix renders e.g. EXISTS (SELECT 1 FROM "items" "items" WHERE "items"."order_id" = "orders"."id" AND
+// for every one-to-many relation, the condition and closing brace follow
+func (a *Adapter) existsPrefix() map[string]string {
+	prefixes := make(map[string]string)
+	for n, r := range a.relations {
+		if !r.Many {
+			continue
+		}
+		var sb strings.Builder
+		sb.WriteString("EXISTS (SELECT 1 FROM ")
+		delimitBuilder(r.Table, &sb)
+		sb.WriteString(" ")
+		delimitBuilder(r.Name, &sb)
+		sb.WriteString(" WHERE ")
+		columnBuilder(r.Name, "", r.ForeignKey, &sb)
+		sb.WriteString(" = ")
+		columnBuilder("", a.tableName, r.LocalKey, &sb)
+		sb.WriteString(" AND ")
+		prefixes[n] = sb.String()
+	}
+	return prefixes
+}
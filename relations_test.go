@@ -0,0 +1,63 @@
+package fiqlsqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type customerRowStruct struct {
+	Name    string `fiql:"name,db:name"`
+	Country string `fiql:"country,db:country_code"`
+}
+
+func customerRelation(join JoinType) func(*Adapter) {
+	return WithRelation(Relation{
+		Name:       "customer",
+		Table:      "customers",
+		LocalKey:   "customer_id",
+		ForeignKey: "id",
+		Join:       join,
+		Fields:     MappingFor(&customerRowStruct{}),
+	})
+}
+
+func TestRelationWhereDeduplicatesJoins(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), customerRelation(""))
+	res, err := adp.Where("customer.name==Jo*;customer.country==AT,id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("customer"."name" LIKE CONCAT($1,'%') AND "customer"."country_code" LIKE $2 OR "orders"."ID" = $3)`, s)
+	assert.Equal(t, []interface{}{"Jo", "AT", 1}, args)
+	assert.Equal(t, `LEFT JOIN "customers" "customer" ON "customer"."id" = "orders"."customer_id"`, res.Joins())
+
+	plain, err := adp.Where("id==2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "", plain.Joins())
+	assert.Equal(t, res.Joins(), plain.And(res, res).Joins())
+}
+
+func TestRelationStatement(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"), customerRelation(JoinInner))
+	res, err := adp.Statement(SelectQuery{Fields: "id,customer.name", Filter: "customer.country==AT", Sort: "customer.name"})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `SELECT [orders].[ID] AS [id], [customer].[name] AS [customer.name] FROM [orders] INNER JOIN [customers] [customer] ON [customer].[id] = [orders].[customer_id] WHERE ([customer].[country_code] LIKE @1) ORDER BY [customer].[name] ASC`, res.Sql())
+}
+
+func TestRelationUnknownField(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), customerRelation(""))
+	_, err := adp.Where("customer.secret==1")
+	assert.Error(t, err)
+	_, err = adp.Where("name==1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"strings"
+)
+
+// scope is a mandatory predicate combined with every where predicate
+type scope struct {
+	column string
+	value  func(ctx context.Context) (interface{}, error)
+}
+
+// WithScope adds a mandatory column = value predicate to every where predicate,
+// a nil value results in column IS NULL.
+// The column may be prefixed with a table name (table.column)
+func WithScope(column string, value interface{}) func(*Adapter) {
+	return WithScopeFunc(column, func(context.Context) (interface{}, error) {
+		return value, nil
+	})
+}
+
+// WithScopeFunc adds a mandatory predicate to every where predicate
+// with the value computed from the context the predicate is generated with,
+// a nil value results in column IS NULL and an error aborts the where generation
+func WithScopeFunc(column string, value func(ctx context.Context) (interface{}, error)) func(*Adapter) {
+	return func(a *Adapter) {
+		a.scopes = append(a.scopes, scope{column: column, value: value})
+	}
+}
+
+// scopeBuilder combines the user predicate with the mandatory scopes,
+// the user predicate is always wrapped in braces so no OR can escape the scope
+func (t *whereBuilder) scopeBuilder(ctx context.Context, scopes []scope) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+	// an empty query results in empty braces which are left out
+	empty := t.comparisons == 0
+	var sb strings.Builder
+	sb.WriteString("(")
+	if !empty {
+		sb.WriteString(t.sb.String())
+	}
+	for i, s := range scopes {
+		v, err := s.value(ctx)
+		if err != nil {
+			return err
+		}
+		if i > 0 || !empty {
+			sb.WriteString(" AND ")
+		}
+		prefix, column := "", s.column
+		if parts := strings.SplitN(s.column, ".", 2); len(parts) == 2 {
+			prefix, column = parts[0], parts[1]
+		}
+		columnBuilder(prefix, t.tableName, column, &sb)
+		if v == nil {
+			sb.WriteString(" IS NULL")
+			continue
+		}
+		t.params = append(t.params, v)
+		t.aliases = append(t.aliases, column)
+		sb.WriteString(" = ")
+		placeholderBuilder(&sb)
+	}
+	sb.WriteString(")")
+	t.sb.Reset()
+	t.sb.WriteString(sb.String())
+	return nil
+}
@@ -0,0 +1,59 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestStaticScopes(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithScope("tenant_id", 7), WithScope("deleted_at", nil))
+	res, err := adp.Where("id==1,id==2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(("ID" = $1 OR "ID" = $2) AND "tenant_id" = $3 AND "deleted_at" IS NULL)`, s)
+	assert.Equal(t, []interface{}{1, 2, 7}, args)
+}
+
+func TestScopesWithEmptyQuery(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithScope("tenant_id", 7), WithScope("deleted_at", nil))
+	res, err := adp.Where("")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("tenant_id" = $1 AND "deleted_at" IS NULL)`, s)
+	assert.Equal(t, []interface{}{7}, args)
+}
+
+func TestScopeFromContext(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"), WithScopeFunc("t.tenant_id", func(ctx context.Context) (interface{}, error) {
+		v, ok := ctx.Value(tenantKey{}).(string)
+		if !ok {
+			return nil, errors.New("no tenant")
+		}
+		return v, nil
+	}))
+	res, err := adp.WhereContext(context.WithValue(context.Background(), tenantKey{}, "acme"), "id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(([orders].[ID] = @1) AND [t].[tenant_id] = @2)`, s)
+	assert.Equal(t, []interface{}{1, "acme"}, args)
+
+	_, err = adp.Where("id==1")
+	assert.EqualError(t, err, "no tenant")
+}
@@ -3,6 +3,9 @@ package fiqlsqladapter
 import (
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 // paramStyle defines how parameters look like in the selected sql dialect
@@ -17,6 +20,19 @@ const atParamStyle paramStyle = "@"
 // standardParamStyle is the standard ? (and yeah sqlite supports that too...)
 const standardParamStyle paramStyle = "?"
 
+// colonParamStyle is used by oracle
+const colonParamStyle paramStyle = ":"
+
+// namedColonParamStyle produces named parameters like :p1 as used by sqlx
+const namedColonParamStyle paramStyle = ":name"
+
+// namedAtParamStyle produces named parameters like @p1 as used by sql.Named with mssql
+const namedAtParamStyle paramStyle = "@name"
+
+func (p paramStyle) named() bool {
+	return p == namedColonParamStyle || p == namedAtParamStyle
+}
+
 // delimiterStyle defines the sql column delimiter
 type delimiterStyle string
 
@@ -26,6 +42,10 @@ const standardSqlDelimiter delimiterStyle = "\"\""
 // angleBracketDelimiter is used by mssql
 const angleBracketDelimiter delimiterStyle = "[]"
 
+// upperCaseSqlDelimiter is the SQL92 delimiter with upper cased identifiers
+// as used by oracle which folds unquoted identifiers to upper case
+const upperCaseSqlDelimiter delimiterStyle = "\"\"^"
+
 // backtickDelimiter is used by mariadb and mysql
 const backtickDelimiter delimiterStyle = "``"
 
@@ -41,7 +61,76 @@ const concatFunctionSupported concatSupport = "concat"
 // concatByPipesSupported inidicates the database uses the double pipe operator for string concatenation
 const concatByPipesSupported concatSupport = "||"
 
-func delimitBuilder(style delimiterStyle, col string, sb *strings.Builder) {
+// sqlDialect holds the specifics of a database system
+type sqlDialect struct {
+	delim      delimiterStyle
+	paramStyle paramStyle
+	concat     concatSupport
+	// emptyStringIsNull indicates that the empty string is NULL like in oracle
+	emptyStringIsNull bool
+	// ilike indicates support for case insensitive ILIKE
+	ilike bool
+	// arrayContains is the format of an array contains check,
+	// %[1]s is the column and %[2]s the parameter, empty if arrays are not supported
+	arrayContains string
+	// timeFunction parses time parameters passed as RFC3339 string, empty if
+	// time parameters are passed as is
+	timeFunction string
+	// nullsOrdering indicates support for NULLS FIRST / NULLS LAST
+	nullsOrdering bool
+	// limit is the style of the limit clause
+	limit limitStyle
+	// unboundedLimit is the limit written if only an offset is given,
+	// empty if OFFSET may be used without LIMIT
+	unboundedLimit string
+	// rowValues indicates support for row value comparisons like (a, b) > (?, ?)
+	rowValues bool
+	// err is set if an unknown dialect was requested,
+	// it is cleared by configuring a known dialect
+	err error
+}
+
+// identifierStart starts an abstract identifier written by the builders,
+// identifiers are delimited by the dialect once the sql is rendered
+const identifierStart = '\x01'
+
+// aliasStart starts an abstract identifier whose case is kept
+// even if the dialect folds identifiers
+const aliasStart = '\x02'
+
+// identifierEnd ends an abstract identifier
+const identifierEnd = '\x03'
+
+// concatStart starts an abstract string concatenation, it is rendered as
+// CONCAT function or with pipes depending on the dialect
+const concatStart = '\x04'
+
+// concatSeparator separates the operands of an abstract string concatenation
+const concatSeparator = '\x05'
+
+// concatEnd ends an abstract string concatenation
+const concatEnd = '\x06'
+
+// delimitBuilder writes an abstract identifier
+func delimitBuilder(col string, sb *strings.Builder) {
+	sb.WriteRune(identifierStart)
+	sb.WriteString(col)
+	sb.WriteRune(identifierEnd)
+}
+
+// aliasBuilder writes an abstract output alias, the case of the alias is kept
+// even if the dialect folds identifiers
+func aliasBuilder(alias string, sb *strings.Builder) {
+	sb.WriteRune(aliasStart)
+	sb.WriteString(alias)
+	sb.WriteRune(identifierEnd)
+}
+
+// quoteBuilder writes the identifier delimited in the given style
+func quoteBuilder(style delimiterStyle, col string, fold bool, sb *strings.Builder) {
+	if style == upperCaseSqlDelimiter && fold {
+		col = strings.ToUpper(col)
+	}
 	switch style {
 	case angleBracketDelimiter:
 		sb.WriteString("[")
@@ -61,11 +150,173 @@ func delimitBuilder(style delimiterStyle, col string, sb *strings.Builder) {
 	}
 }
 
+// renderSql replaces the abstract identifiers and concatenations
+// with the syntax of the dialect
+func renderSql(style delimiterStyle, concat concatSupport, sql string) string {
+	if !strings.ContainsAny(sql, string([]rune{identifierStart, aliasStart, concatStart})) {
+		return sql
+	}
+	var sb strings.Builder
+	for i := 0; i < len(sql); i++ {
+		switch sql[i] {
+		case identifierStart, aliasStart:
+			end := i + strings.IndexRune(sql[i:], identifierEnd)
+			quoteBuilder(style, sql[i+1:end], sql[i] == identifierStart, &sb)
+			i = end
+		case concatStart:
+			end := i + strings.IndexRune(sql[i:], concatEnd)
+			operands := strings.Split(renderSql(style, concat, sql[i+1:end]), string(concatSeparator))
+			if concat == concatFunctionSupported {
+				sb.WriteString("CONCAT(")
+				sb.WriteString(strings.Join(operands, ","))
+				sb.WriteString(")")
+			} else {
+				sb.WriteString(strings.Join(operands, " || "))
+			}
+			i = end
+		default:
+			sb.WriteByte(sql[i])
+		}
+	}
+	return sb.String()
+}
+
+// expressionBuilder writes a trusted expression, {name} is written as
+// identifier delimited by the dialect, dots separate qualified names
+// e.g. {o.amount}, {{ is written as a literal {
+func expressionBuilder(expression string, sb *strings.Builder) {
+	for i := 0; i < len(expression); i++ {
+		if expression[i] != '{' {
+			sb.WriteByte(expression[i])
+			continue
+		}
+		if strings.HasPrefix(expression[i:], "{{") {
+			sb.WriteByte('{')
+			i++
+			continue
+		}
+		end := strings.IndexByte(expression[i:], '}')
+		if end < 0 {
+			sb.WriteString(expression[i:])
+			return
+		}
+		for j, part := range strings.Split(expression[i+1:i+end], ".") {
+			if j > 0 {
+				sb.WriteRune('.')
+			}
+			delimitBuilder(part, sb)
+		}
+		i += end
+	}
+}
+
+// renderIdentifiers replaces the abstract identifiers of clauses
+// which do not contain any concatenation
+func renderIdentifiers(style delimiterStyle, sql string) string {
+	return renderSql(style, "", sql)
+}
+
+// columnBuilder writes the delimited column qualified by the table prefix
+// or if there is none by the table name
+func columnBuilder(tablePrefix, tableName, col string, sb *strings.Builder) {
+	if tablePrefix != "" {
+		delimitBuilder(tablePrefix, sb)
+		sb.WriteRune('.')
+	} else if tableName != "" {
+		delimitBuilder(tableName, sb)
+		sb.WriteRune('.')
+	}
+	delimitBuilder(col, sb)
+}
+
+// placeholder is the abstract placeholder written by the builders,
+// it is replaced by the dialects parameter style once the sql is rendered
+const placeholder = '\x00'
+
+// placeholderBuilder writes an abstract placeholder
+func placeholderBuilder(sb *strings.Builder) {
+	sb.WriteRune(placeholder)
+}
+
+// valueBuilder writes the placeholder for a value and returns the value to bind,
+// time values are passed as string to the time function if the dialect has one
+func (d sqlDialect) valueBuilder(v interface{}, sb *strings.Builder) interface{} {
+	if t, ok := v.(time.Time); ok && d.timeFunction != "" {
+		sb.WriteString(d.timeFunction)
+		sb.WriteString("(")
+		placeholderBuilder(sb)
+		sb.WriteString(")")
+		return t.Format(time.RFC3339Nano)
+	}
+	placeholderBuilder(sb)
+	return v
+}
+
+// renderPlaceholders replaces the abstract placeholders with the parameter style
+// numbering them starting after offset, named styles take the names from parameterName
+func renderPlaceholders(style paramStyle, sql string, offset int, aliases []string, aliasNames bool) string {
+	if !strings.ContainsRune(sql, placeholder) {
+		return sql
+	}
+	var sb strings.Builder
+	i := 0
+	for _, r := range sql {
+		if r == placeholder {
+			if style.named() {
+				sb.WriteString(string(style[0]))
+				sb.WriteString(parameterName(aliases, aliasNames, i, offset))
+			} else {
+				parameterBuilder(style, offset+i+1, &sb)
+			}
+			i++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// parameterName returns the name of the i-th parameter,
+// either p1..pn or if aliasNames is set the alias suffixed with the number
+func parameterName(aliases []string, aliasNames bool, i, offset int) string {
+	n := strconv.Itoa(offset + i + 1)
+	if !aliasNames || i >= len(aliases) || aliases[i] == "" {
+		return "p" + n
+	}
+	var sb strings.Builder
+	for _, r := range aliases[i] {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	sb.WriteRune('_')
+	sb.WriteString(n)
+	return sb.String()
+}
+
+// fieldBuilder writes the qualified column of a field,
+// it is shared by all clauses so columns are rendered the same everywhere,
+// expressions are trusted and wrapped in braces so they keep their precedence
+func fieldBuilder(tableName string, f Field, sb *strings.Builder) {
+	if f.Expression != "" {
+		sb.WriteString("(")
+		expressionBuilder(f.Expression, sb)
+		sb.WriteString(")")
+		return
+	}
+	columnBuilder(f.TablePrefix, tableName, f.Db, sb)
+}
+
 func parameterBuilder(style paramStyle, len int, sb *strings.Builder) {
 	switch style {
 	case dollarParamStyle:
 		sb.WriteString("$")
 		sb.WriteString(strconv.Itoa(len))
+	case colonParamStyle:
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(len))
 	case atParamStyle:
 		sb.WriteString("@")
 		sb.WriteString(strconv.Itoa(len))
@@ -76,79 +327,231 @@ func parameterBuilder(style paramStyle, len int, sb *strings.Builder) {
 
 // Dialect constants
 const (
-	DialectMariaDB  = "maria"
-	DialectMySQL    = "mysql"
-	DialectMSSL     = "mssql"
-	DialectSQLite   = "sqlite3"
-	DialectPostgres = "postgres"
+	DialectMariaDB    = "maria"
+	DialectMySQL      = "mysql"
+	DialectMSSL       = "mssql"
+	DialectSQLite     = "sqlite3"
+	DialectPostgres   = "postgres"
+	DialectOracle     = "oracle"
+	DialectClickHouse = "clickhouse"
+	DialectDuckDB     = "duckdb"
+	DialectSQL92      = "sql92"
 )
 
-// WithDialect configures which dialect to be used
+var dialectsMu sync.RWMutex
+
+// dialects is the registry of dialect options by lower cased name
+var dialects = map[string]func(*Adapter){}
+
+func init() {
+	RegisterDialect(WithDialectMariaDB(), DialectMariaDB, "mariadb", DialectMySQL)
+	RegisterDialect(WithDialectPostgres(), DialectPostgres, "postgresql", "pgx", "pq")
+	RegisterDialect(WithDialectMSSQL(), DialectMSSL, "sqlserver")
+	RegisterDialect(WithDialectSQLite(), DialectSQLite, "sqlite")
+	RegisterDialect(WithDialectOracle(), DialectOracle, "godror")
+	RegisterDialect(WithDialectClickHouse(), DialectClickHouse)
+	RegisterDialect(WithDialectDuckDB(), DialectDuckDB)
+	RegisterDialect(WithDialectSQL92(), DialectSQL92)
+}
+
+// RegisterDialect registers a dialect option under the given names,
+// names are case insensitive and existing registrations are replaced
+func RegisterDialect(option func(*Adapter), names ...string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	for _, n := range names {
+		dialects[strings.ToLower(n)] = option
+	}
+}
+
+// LookupDialect returns the dialect option registered under the given name
+func LookupDialect(name string) (func(*Adapter), error) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	if o, ok := dialects[strings.ToLower(name)]; ok {
+		return o, nil
+	}
+	return nil, newError(ErrorKindUnknownDialect, name, nil)
+}
+
+// WithDialect configures which dialect to be used by its registered name,
+// an unknown dialect makes the adapter return an error on use
+// until a known dialect is configured
 func WithDialect(dialect string) func(*Adapter) {
-	switch dialect {
-	case DialectMariaDB:
-	case DialectMySQL:
-		return WithDialectMariaDB()
-	case DialectPostgres:
-		return WithDialectPostgres()
-	case DialectMSSL:
-		return WithDialectMSSQL()
-	case DialectSQLite:
-		return WithDialectSQLite()
+	option, err := LookupDialect(dialect)
+	if err != nil {
+		return func(a *Adapter) {
+			a.sqlDialect.err = err
+		}
 	}
-	return WithDialectSQL92()
+	return option
 }
 
 // WithDialectMSSQL configures MSSQL delimiters and params
 func WithDialectMSSQL() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = angleBracketDelimiter
-		a.paramStyle = atParamStyle
-		a.concat = concatFunctionSupported
+		a.sqlDialect = sqlDialect{
+			delim:      angleBracketDelimiter,
+			paramStyle: atParamStyle,
+			concat:     concatFunctionSupported,
+			limit:      offsetFetchNextLimit,
+		}
 	}
 }
 
 // WithDialectSQLite configures SQLite delimiters and params
 func WithDialectSQLite() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = standardSqlDelimiter
-		a.paramStyle = standardParamStyle
-		a.concat = concatByPipesSupported
+		a.sqlDialect = sqlDialect{
+			delim:          standardSqlDelimiter,
+			paramStyle:     standardParamStyle,
+			concat:         concatByPipesSupported,
+			nullsOrdering:  true,
+			rowValues:      true,
+			unboundedLimit: "-1",
+		}
 	}
 }
 
 // WithDialectPostgres configures Postgres delimiters and params
 func WithDialectPostgres() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = standardSqlDelimiter
-		a.paramStyle = dollarParamStyle
-		a.concat = concatFunctionSupported
+		a.sqlDialect = sqlDialect{
+			delim:         standardSqlDelimiter,
+			paramStyle:    dollarParamStyle,
+			concat:        concatFunctionSupported,
+			ilike:         true,
+			arrayContains: "%[2]s = ANY(%[1]s)",
+			nullsOrdering: true,
+			rowValues:     true,
+		}
 	}
 }
 
 // WithDialectMariaDB configures MariaDB / MySql delimiters and params
 func WithDialectMariaDB() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = backtickDelimiter
-		a.paramStyle = standardParamStyle
-		a.concat = concatFunctionSupported
+		a.sqlDialect = sqlDialect{
+			delim:          backtickDelimiter,
+			paramStyle:     standardParamStyle,
+			concat:         concatFunctionSupported,
+			rowValues:      true,
+			unboundedLimit: "18446744073709551615",
+		}
 	}
 }
 
 // WithDialectSQL92 means column delimiter is " and parameters are ?
 func WithDialectSQL92() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = standardSqlDelimiter
-		a.paramStyle = standardParamStyle
-		a.concat = concatFunctionSupported
+		a.sqlDialect = sqlDialect{
+			delim:         standardSqlDelimiter,
+			paramStyle:    standardParamStyle,
+			concat:        concatFunctionSupported,
+			nullsOrdering: true,
+			rowValues:     true,
+		}
 	}
 }
 
 // WithDialectSQL92NoDelimiter means no column delimiter is used and parameters are ?
 func WithDialectSQL92NoDelimiter() func(*Adapter) {
 	return func(a *Adapter) {
-		a.delim = noDelimiter
-		a.paramStyle = standardParamStyle
-		a.concat = concatFunctionSupported
+		a.sqlDialect = sqlDialect{
+			delim:         noDelimiter,
+			paramStyle:    standardParamStyle,
+			concat:        concatFunctionSupported,
+			nullsOrdering: true,
+			rowValues:     true,
+		}
+	}
+}
+
+// WithDialectOracle configures Oracle delimiters and params,
+// identifiers are upper cased and empty strings are compared as NULL
+func WithDialectOracle() func(*Adapter) {
+	return func(a *Adapter) {
+		a.sqlDialect = sqlDialect{
+			delim:             upperCaseSqlDelimiter,
+			paramStyle:        colonParamStyle,
+			concat:            concatByPipesSupported,
+			emptyStringIsNull: true,
+			nullsOrdering:     true,
+			limit:             offsetFetchFirstLimit,
+		}
+	}
+}
+
+// WithDialectClickHouse configures ClickHouse delimiters and params,
+// array fields are checked with has() and time parameters are parsed by the server
+func WithDialectClickHouse() func(*Adapter) {
+	return func(a *Adapter) {
+		a.sqlDialect = sqlDialect{
+			delim:         backtickDelimiter,
+			paramStyle:    standardParamStyle,
+			concat:        concatFunctionSupported,
+			ilike:         true,
+			arrayContains: "has(%[1]s, %[2]s)",
+			timeFunction:  "parseDateTime64BestEffort",
+			nullsOrdering: true,
+			rowValues:     true,
+		}
+	}
+}
+
+// WithDialectDuckDB configures DuckDB delimiters and params,
+// array fields are checked with list_contains()
+func WithDialectDuckDB() func(*Adapter) {
+	return func(a *Adapter) {
+		a.sqlDialect = sqlDialect{
+			delim:         standardSqlDelimiter,
+			paramStyle:    dollarParamStyle,
+			concat:        concatByPipesSupported,
+			ilike:         true,
+			arrayContains: "list_contains(%[1]s, %[2]s)",
+			nullsOrdering: true,
+			rowValues:     true,
+		}
+	}
+}
+
+// WithCaseInsensitiveLike uses ILIKE for string equality on dialects supporting it,
+// other dialects keep using LIKE and rely on the collation
+func WithCaseInsensitiveLike() func(*Adapter) {
+	return func(a *Adapter) {
+		a.caseInsensitive = true
+	}
+}
+
+// WithNamedParams configures named parameters :p1..:pn as used by sqlx,
+// it takes precedence over the parameter style of the dialect
+func WithNamedParams() func(*Adapter) {
+	return func(a *Adapter) {
+		a.namedParams = namedColonParamStyle
+	}
+}
+
+// WithNamedParamsAt configures named parameters @p1..@pn as used by sql.Named with mssql,
+// it takes precedence over the parameter style of the dialect
+func WithNamedParamsAt() func(*Adapter) {
+	return func(a *Adapter) {
+		a.namedParams = namedAtParamStyle
+	}
+}
+
+// params returns the parameter style, named parameters are kept
+// outside of the dialect so the order of the options does not matter
+func (a *Adapter) params() paramStyle {
+	if a.namedParams != "" {
+		return a.namedParams
+	}
+	return a.paramStyle
+}
+
+// WithAliasParamNames names parameters after the selector alias e.g. :amt_1
+// instead of :p1, it only applies to named parameters
+func WithAliasParamNames() func(*Adapter) {
+	return func(a *Adapter) {
+		a.aliasParamNames = true
 	}
 }
@@ -0,0 +1,192 @@
+package fiqlsqladapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectOracle(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect(DialectOracle), WithTableName("orders"))
+	res, err := adp.Where("id==1;tx==*0010*")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("ORDERS"."ID" = :1 AND "ORDERS"."TX" LIKE '%' || :2 || '%')`, s)
+	assert.Equal(t, []interface{}{1, "0010"}, args)
+}
+
+func TestDialectOracleEmptyStringIsNull(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectOracle())
+	res, err := adp.Where(`id==2;tx!=\`)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("ID" = :1 AND "TX" IS NOT NULL)`, s)
+	assert.Equal(t, []interface{}{2}, args)
+	res, err = adp.Where(`tx==\`)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("TX" IS NULL)`, res.Sql())
+	assert.Empty(t, res.Parameters())
+}
+
+func TestDialectEmptyStringIsNotNullElsewhere(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	res, err := adp.Where(`tx==\`)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("Tx" LIKE $1)`, res.Sql())
+	assert.Equal(t, []interface{}{""}, res.Parameters())
+}
+
+type analyticsRowStruct struct {
+	ID      int       `fiql:"id,db:id"`
+	Name    string    `fiql:"name,db:name"`
+	Tags    []string  `fiql:"tag,db:tags"`
+	Created time.Time `fiql:"cre,db:created_at"`
+}
+
+func TestDialectAnalyticsGolden(t *testing.T) {
+	tests := []struct {
+		dialect func(*Adapter)
+		query   string
+		sql     string
+		params  []interface{}
+	}{
+		{WithDialectClickHouse(), "name==jo*;id=gt=2", "(`name` LIKE CONCAT(?,'%') AND `id` > ?)", []interface{}{"jo", 2}},
+		{WithDialectClickHouse(), "tag==red,tag!=blue", "(has(`tags`, ?) OR NOT has(`tags`, ?))", []interface{}{"red", "blue"}},
+		{WithDialectClickHouse(), "cre=ge=2022-09-16T10:15:04Z", "(`created_at` >= parseDateTime64BestEffort(?))", []interface{}{"2022-09-16T10:15:04Z"}},
+		{WithDialectDuckDB(), "name==*jo*;id=gt=2", `("name" LIKE '%' || $1 || '%' AND "id" > $2)`, []interface{}{"jo", 2}},
+		{WithDialectDuckDB(), "tag==red", `(list_contains("tags", $1))`, []interface{}{"red"}},
+		{WithDialectDuckDB(), "cre=ge=2022-09-16T10:15:04Z", `("created_at" >= $1)`, []interface{}{time.Date(2022, 9, 16, 10, 15, 4, 0, time.UTC)}},
+		{WithDialectPostgres(), "tag==red", `($1 = ANY("tags"))`, []interface{}{"red"}},
+	}
+	for _, tt := range tests {
+		adp := NewAdapterFor(&analyticsRowStruct{}, tt.dialect)
+		res, err := adp.Where(tt.query)
+		assert.NoError(t, err, tt.query)
+		if err != nil {
+			continue
+		}
+		s, params, err := res.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.sql, s, tt.query)
+		assert.Equal(t, tt.params, params, tt.query)
+	}
+}
+
+func TestDialectCaseInsensitiveLike(t *testing.T) {
+	adp := NewAdapterFor(&analyticsRowStruct{}, WithDialect(DialectDuckDB), WithCaseInsensitiveLike())
+	res, err := adp.Where("name==jo")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("name" ILIKE $1)`, res.Sql())
+	adp = NewAdapterFor(&analyticsRowStruct{}, WithDialectMSSQL(), WithCaseInsensitiveLike())
+	res, err = adp.Where("name==jo")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `([name] LIKE @1)`, res.Sql())
+}
+
+func TestDialectArrayUnsupported(t *testing.T) {
+	adp := NewAdapterFor(&analyticsRowStruct{}, WithDialectMSSQL())
+	_, err := adp.Where("tag==red")
+	assert.Error(t, err)
+	adp = NewAdapterFor(&analyticsRowStruct{}, WithDialectClickHouse())
+	_, err = adp.Where("tag=gt=1")
+	assert.Error(t, err)
+	_, err = adp.Where("tag==re*")
+	assert.Error(t, err)
+}
+
+func TestWithDialectMariaDB(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect(DialectMariaDB))
+	res, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "(`ID` = ?)", res.Sql())
+}
+
+func TestWithDialectAliases(t *testing.T) {
+	for alias, expected := range map[string]string{
+		"postgresql": `("ID" = $1)`,
+		"PGX":        `("ID" = $1)`,
+		"sqlserver":  `([ID] = @1)`,
+		"sqlite":     `("ID" = ?)`,
+	} {
+		adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect(alias))
+		res, err := adp.Where("id==1")
+		assert.NoError(t, err, alias)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, expected, res.Sql(), alias)
+	}
+}
+
+func TestWithDialectUnknown(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect("db2"))
+	_, err := adp.Where("id==1")
+	assert.EqualError(t, err, "unknown dialect: db2")
+	_, err = adp.OrderBy("id")
+	assert.Error(t, err)
+	_, err = LookupDialect("db2")
+	assert.Error(t, err)
+}
+
+func unregisterDialects(names ...string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	for _, n := range names {
+		delete(dialects, n)
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	RegisterDialect(WithDialectPostgres(), "cockroach", "crdb")
+	t.Cleanup(func() { unregisterDialects("cockroach", "crdb") })
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect("crdb"))
+	res, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("ID" = $1)`, res.Sql())
+	_, _, err = res.ToSqlFor(WithDialect("db2"))
+	assert.Error(t, err)
+}
+
+func TestKnownDialectClearsUnknownDialect(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialect("bad"), WithDialectPostgres())
+	res, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("ID" = $1)`, res.Sql())
+
+	adp.Update(WithDialect("bad"))
+	_, err = adp.Where("id==1")
+	assert.Error(t, err)
+	adp.Update(WithDialect(DialectSQLite))
+	_, err = adp.Where("id==1")
+	assert.NoError(t, err)
+}
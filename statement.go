@@ -0,0 +1,127 @@
+package fiqlsqladapter
+
+import (
+	"context"
+	"strings"
+)
+
+// SelectQuery holds the raw inputs of a select statement
+// as they are usually passed as query parameters
+type SelectQuery struct {
+	// Fields is a comma separated list of aliases, empty selects all permitted fields
+	Fields string
+	// Filter is a fiql query, empty selects all rows
+	Filter string
+	// Sort is a order by query, empty uses the default order
+	Sort string
+	// Page limits the returned rows, the page size bounds of the adapter apply
+	Page Page
+}
+
+// Statement generates a complete SELECT statement for the table of the adapter
+func (a *Adapter) Statement(q SelectQuery) (*Statement, error) {
+	return a.StatementContext(context.Background(), q)
+}
+
+// StatementContext generates a complete SELECT statement for the table of the adapter,
+// the permissions of the context apply to all parts of the statement
+func (a *Adapter) StatementContext(ctx context.Context, q SelectQuery) (*Statement, error) {
+	if err := a.statementErr(); err != nil {
+		return nil, err
+	}
+	sel, err := a.SelectContext(ctx, q.Fields)
+	if err != nil {
+		return nil, err
+	}
+	p, err := a.filterPredicate(ctx, q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	ob, err := a.OrderByContext(ctx, q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	l, err := a.Limit(q.Page)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(sel.sql)
+	joins := appendUnique(appendUnique(appendUnique(nil, sel.joins...), p.joins...), ob.joins...)
+	a.fromWhereBuilder(p, joins, &sb)
+	if ob.sql != "" {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(ob.sql)
+	} else if l.sql != "" && a.limit == offsetFetchNextLimit {
+		// mssql does not allow OFFSET FETCH without ORDER BY
+		sb.WriteString(" ORDER BY (SELECT NULL)")
+	}
+	if l.sql != "" {
+		sb.WriteString(" ")
+		sb.WriteString(l.sql)
+	}
+	p.sql = sb.String()
+	return &Statement{p: p}, nil
+}
+
+// CountStatement generates a SELECT COUNT(*) statement matching
+// the rows of the statement without its page
+func (a *Adapter) CountStatement(q SelectQuery) (*Statement, error) {
+	return a.CountStatementContext(context.Background(), q)
+}
+
+// CountStatementContext generates a SELECT COUNT(*) statement matching
+// the rows of the statement without its page
+func (a *Adapter) CountStatementContext(ctx context.Context, q SelectQuery) (*Statement, error) {
+	if err := a.statementErr(); err != nil {
+		return nil, err
+	}
+	p, err := a.filterPredicate(ctx, q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	sb.WriteString("SELECT COUNT(*)")
+	a.fromWhereBuilder(p, p.joins, &sb)
+	p.sql = sb.String()
+	return &Statement{p: p}, nil
+}
+
+func (a *Adapter) statementErr() error {
+	if err := a.check(); err != nil {
+		return err
+	}
+	if a.tableName == "" {
+		return newError(ErrorKindConfiguration, "table name", nil)
+	}
+	return nil
+}
+
+// filterPredicate generates the predicate of the filter,
+// if there is no filter it only holds the scopes
+func (a *Adapter) filterPredicate(ctx context.Context, filter string) (*WherePredicate, error) {
+	if filter != "" {
+		return a.WhereContext(ctx, filter)
+	}
+	wb := a.newWhereBuilder(ctx)
+	if err := wb.scopeBuilder(ctx, a.scopes); err != nil {
+		return nil, err
+	}
+	return a.predicate(wb), nil
+}
+
+// fromWhereBuilder writes the FROM with the joins and the WHERE part,
+// the WHERE is omitted if there is no filter and no scope
+func (a *Adapter) fromWhereBuilder(p *WherePredicate, joins []string, sb *strings.Builder) {
+	sb.WriteString(" FROM ")
+	delimitBuilder(a.tableName, sb)
+	for _, j := range joins {
+		sb.WriteString(" ")
+		sb.WriteString(j)
+	}
+	if p.sql != "" {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(p.sql)
+	}
+}
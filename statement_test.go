@@ -0,0 +1,60 @@
+package fiqlsqladapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementPostgres(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"), WithPageSize(20, 100))
+	q := SelectQuery{Fields: "id,amt", Filter: "amt=gt=10;cur==EUR", Sort: "-cre", Page: Page{Offset: 40}}
+	res, err := adp.Statement(q)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := res.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "orders"."ID" AS "id", "orders"."amount" AS "amt" FROM "orders" WHERE ("orders"."amount" > $1 AND "orders"."Currency" = $2) ORDER BY "orders"."created_at" DESC LIMIT 20 OFFSET 40`, s)
+	assert.Equal(t, []interface{}{10.0, "EUR"}, args)
+
+	count, err := adp.CountStatement(q)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `SELECT COUNT(*) FROM "orders" WHERE ("orders"."amount" > $1 AND "orders"."Currency" = $2)`, count.Sql())
+}
+
+func TestStatementMSSQLWithoutOrder(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"), WithScope("tenant_id", 7))
+	res, err := adp.Statement(SelectQuery{Page: Page{Limit: 10}})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `SELECT [orders].[amount] AS [amt], [orders].[created_at] AS [cre], [orders].[Currency] AS [cur], [orders].[fee] AS [fee], [orders].[ID] AS [id], [orders].[Tx] AS [tx], [orders].[updated_at] AS [upd] FROM [orders] WHERE ([orders].[tenant_id] = @1) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`, res.Sql())
+	assert.Equal(t, []interface{}{7}, res.Parameters())
+}
+
+func TestStatementMSSQLFields(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithTableName("orders"), WithScope("tenant_id", 7))
+	res, err := adp.Statement(SelectQuery{Fields: "id", Page: Page{Limit: 10}})
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `SELECT [orders].[ID] AS [id] FROM [orders] WHERE ([orders].[tenant_id] = @1) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`, res.Sql())
+	assert.Equal(t, []interface{}{7}, res.Parameters())
+}
+
+func TestStatementErrors(t *testing.T) {
+	_, err := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres()).Statement(SelectQuery{})
+	assert.Error(t, err)
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithTableName("orders"))
+	_, err = adp.Statement(SelectQuery{Fields: "id,nope"})
+	assert.Error(t, err)
+	_, err = adp.CountStatement(SelectQuery{Filter: "nope==1"})
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package fiqlsqladapter
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -77,3 +78,69 @@ func TestDbTagsAndDoubleTablePrefixFromTimeStruct(t *testing.T) {
 	tags := tagsFromStruct(withDbtagAndTableDoublePrefixStruct{})
 	assert.Equal(t, FieldMapping(FieldMapping{"time": Field{Db: "figgety.flop", Alias: "time", Type: timeType, TablePrefix: "mytable"}}), tags)
 }
+
+type withOpsStruct struct {
+	Email string `fiql:"email,db:email,ops:eq|NE"`
+}
+
+func TestOpsTagsFromStruct(t *testing.T) {
+	tags := tagsFromStruct(withOpsStruct{})
+	assert.Equal(t, FieldMapping{"email": Field{Db: "email", Alias: "email", Type: stringType, Comparisons: []Comparison{ComparisonEq, ComparisonNe}}}, tags)
+}
+
+type withUnknownOpsStruct struct {
+	Email string `fiql:"email,db:email,ops:eq|in"`
+}
+
+func TestUnknownOpsTagFailsAdapter(t *testing.T) {
+	_, err := NewAdapterFor(&withUnknownOpsStruct{}, WithDialectPostgres()).Where("email==a")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	if e == nil {
+		return
+	}
+	assert.Equal(t, ErrorKindUnknownComparison, e.Kind)
+	assert.Equal(t, "in", e.Value)
+}
+
+func TestUnknownAllowedComparisonFailsAdapter(t *testing.T) {
+	m := NewMappingBuilder().AddStringMapping("email", "email").AllowComparisons("email", ComparisonEq, Comparison("like")).Build()
+	_, err := NewAdapter(m).OrderBy("email")
+	assert.Error(t, err)
+	assert.Equal(t, "unknown comparison in mapping: like", err.Error())
+}
+
+type withWildcardStruct struct {
+	Tx   string `fiql:"tx,noleadingwildcard,minwildcard:3"`
+	Mail string `fiql:"mail,nowildcard"`
+}
+
+func TestWildcardTagsFromStruct(t *testing.T) {
+	tags := tagsFromStruct(withWildcardStruct{})
+	assert.Equal(t, FieldMapping{
+		"tx":   Field{Db: "Tx", Alias: "tx", Type: stringType, Wildcards: WildcardsNoLeading, MinWildcardLength: 3},
+		"mail": Field{Db: "Mail", Alias: "mail", Type: stringType, Wildcards: WildcardsForbidden},
+	}, tags)
+}
+
+type withNullsStruct struct {
+	Updated *time.Time `fiql:"upd,db:updated_at,nullslast"`
+}
+
+func TestNullsTagsFromStruct(t *testing.T) {
+	tags := tagsFromStruct(withNullsStruct{})
+	assert.Equal(t, FieldMapping{"upd": Field{Db: "updated_at", Alias: "upd", Type: timePtrType, Nulls: NullsLast}}, tags)
+}
+
+type withFilterSortStruct struct {
+	Note string `fiql:"note,nosort"`
+	Hash string `fiql:"hash,nofilter"`
+}
+
+func TestFilterSortTagsFromStruct(t *testing.T) {
+	tags := tagsFromStruct(withFilterSortStruct{})
+	assert.Equal(t, FieldMapping{
+		"note": Field{Db: "Note", Alias: "note", Type: stringType, NoSort: true},
+		"hash": Field{Db: "Hash", Alias: "hash", Type: stringType, NoFilter: true},
+	}, tags)
+}
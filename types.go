@@ -1,31 +1,268 @@
 package fiqlsqladapter
 
-import "fmt"
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
 
 // WherePredicate holds the content of a where predicate
 // it will always be wrapped in outter most braces to avoid
 // issues with OR chaining leading to unwated results
 // it does not contain the WHERE keyword
 type WherePredicate struct {
-	sql    string
-	params []interface{}
+	sql        string
+	params     []interface{}
+	aliases    []string
+	style      paramStyle
+	offset     int
+	aliasNames bool
+	delim      delimiterStyle
+	concat     concatSupport
+	usage      dialectUsage
+	joins      []string
+}
+
+// dialectUsage records the dialect specific constructs a predicate was built with,
+// unlike placeholders, identifiers and concatenation they are not abstract
+// so the predicate can only be rebound to a dialect rendering them the same way
+type dialectUsage struct {
+	ilike bool
+	// arrayContains is the format of the written array contains checks
+	arrayContains string
+	times         bool
+	timeFunction  string
+	// emptyString is set if a field was compared with the empty string
+	emptyString       bool
+	emptyStringIsNull bool
+	rowValues         bool
+}
+
+// of captures the settings of the dialect the used constructs depend on
+func (u dialectUsage) of(d sqlDialect) dialectUsage {
+	if u.times {
+		u.timeFunction = d.timeFunction
+	}
+	if u.emptyString {
+		u.emptyStringIsNull = d.emptyStringIsNull
+	}
+	return u
+}
+
+func (u dialectUsage) merge(o dialectUsage) dialectUsage {
+	u.ilike = u.ilike || o.ilike
+	if o.arrayContains != "" {
+		u.arrayContains = o.arrayContains
+	}
+	if o.times {
+		u.times, u.timeFunction = true, o.timeFunction
+	}
+	if o.emptyString {
+		u.emptyString, u.emptyStringIsNull = true, o.emptyStringIsNull
+	}
+	u.rowValues = u.rowValues || o.rowValues
+	return u
+}
+
+// unsupported returns the first used construct the dialect renders differently,
+// empty if the predicate can be rendered for the dialect
+func (u dialectUsage) unsupported(d sqlDialect) string {
+	switch {
+	case u.ilike && !d.ilike:
+		return "ILIKE"
+	case u.arrayContains != "" && u.arrayContains != d.arrayContains:
+		return "array contains"
+	case u.times && u.timeFunction != d.timeFunction:
+		return "time parameters"
+	case u.emptyString && u.emptyStringIsNull != d.emptyStringIsNull:
+		return "empty string"
+	case u.rowValues && !d.rowValues:
+		return "row values"
+	}
+	return ""
+}
+
+// RawPredicate creates a where predicate from hand written sql
+// to be combined with generated predicates, every ? is treated as placeholder,
+// a literal ? e.g. in a string or a jsonb operator like ?| has to be written as ??
+func RawPredicate(query string, params ...interface{}) *WherePredicate {
+	var sb strings.Builder
+	for i := 0; i < len(query); i++ {
+		if query[i] != '?' {
+			sb.WriteByte(query[i])
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == '?' {
+			sb.WriteByte('?')
+			i++
+			continue
+		}
+		placeholderBuilder(&sb)
+	}
+	return &WherePredicate{
+		sql:    sb.String(),
+		params: params,
+		style:  standardParamStyle,
+		delim:  standardSqlDelimiter,
+		concat: concatFunctionSupported,
+	}
+}
+
+func (w *WherePredicate) render() string {
+	return renderPlaceholders(w.style, renderSql(w.delim, w.concat, w.sql), w.offset, w.aliases, w.aliasNames)
+}
+
+// args returns the parameters, as sql.NamedArg if a named parameter style is used
+func (w *WherePredicate) args() []interface{} {
+	if !w.style.named() {
+		return w.params
+	}
+	args := make([]interface{}, len(w.params))
+	for i, v := range w.NamedArgs() {
+		args[i] = v
+	}
+	return args
 }
 
 // ToSql returns the query string and the parameters
 // satisfies sqlizer https://pkg.go.dev/github.com/masterminds/squirrel#Sqlizer
+// with named parameters the parameters are sql.NamedArg
 func (w *WherePredicate) ToSql() (string, []interface{}, error) {
-	return w.sql, w.params, nil
+	return w.render(), w.args(), nil
 }
 
 // Query satisfies querier interface from ent
 // https://pkg.go.dev/entgo.io/ent@v0.12.4/dialect/sql#Querier
 func (w *WherePredicate) Query() (string, []any) {
-	return w.sql, w.params
+	return w.render(), w.args()
+}
+
+// NamedArgs returns the parameters as sql.NamedArg
+// named like the placeholders of named parameter styles
+func (w *WherePredicate) NamedArgs() []sql.NamedArg {
+	args := make([]sql.NamedArg, len(w.params))
+	for i, v := range w.params {
+		args[i] = sql.Named(parameterName(w.aliases, w.aliasNames, i, w.offset), v)
+	}
+	return args
+}
+
+// NamedParams returns the parameters as map as used by sqlx named queries
+// named like the placeholders of named parameter styles
+func (w *WherePredicate) NamedParams() map[string]interface{} {
+	params := make(map[string]interface{}, len(w.params))
+	for i, v := range w.params {
+		params[parameterName(w.aliases, w.aliasNames, i, w.offset)] = v
+	}
+	return params
 }
 
 // Sql returns the underlying sql string
 func (w *WherePredicate) Sql() string {
-	return w.sql
+	return w.render()
+}
+
+// Rebind returns a copy of the predicate rendered with the parameter style,
+// the identifier delimiters and the string concatenation of the given
+// dialect option e.g. Rebind(WithDialectSQLite()), other dialect specific
+// constructs like ILIKE or array checks are kept as built so an
+// ErrorKindUnsupportedByDialect error is returned if the dialect renders them differently
+func (w *WherePredicate) Rebind(dialect func(*Adapter)) (*WherePredicate, error) {
+	a := &Adapter{sqlDialect: sqlDialect{paramStyle: standardParamStyle, delim: standardSqlDelimiter}}
+	dialect(a)
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if construct := w.usage.unsupported(a.sqlDialect); construct != "" {
+		return nil, newError(ErrorKindUnsupportedByDialect, construct, nil)
+	}
+	c := *w
+	c.style = a.params()
+	c.aliasNames = a.aliasParamNames
+	c.delim = a.delim
+	c.concat = a.concat
+	return &c, nil
+}
+
+// ToSqlFor returns the query string and the parameters
+// rendered for the given dialect option
+func (w *WherePredicate) ToSqlFor(dialect func(*Adapter)) (string, []interface{}, error) {
+	c, err := w.Rebind(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.ToSql()
+}
+
+// WithOffset returns a copy of the predicate with its placeholders numbered
+// starting after offset, use it if the predicate follows other parameters
+func (w *WherePredicate) WithOffset(offset int) *WherePredicate {
+	c := *w
+	c.offset = offset
+	return &c
+}
+
+// And combines the predicates with AND,
+// the parameter style and offset of the receiver are kept
+func (w *WherePredicate) And(others ...*WherePredicate) *WherePredicate {
+	return w.combine(" AND ", others)
+}
+
+// Or combines the predicates with OR,
+// the parameter style and offset of the receiver are kept
+func (w *WherePredicate) Or(others ...*WherePredicate) *WherePredicate {
+	return w.combine(" OR ", others)
+}
+
+// Not negates the predicate
+func (w *WherePredicate) Not() *WherePredicate {
+	c := *w
+	c.sql = "(NOT " + w.sql + ")"
+	return &c
+}
+
+func (w *WherePredicate) combine(operator string, others []*WherePredicate) *WherePredicate {
+	var sb strings.Builder
+	params := append(make([]interface{}, 0, len(w.params)), w.params...)
+	aliases := w.paddedAliases()
+	joins := appendUnique(nil, w.joins...)
+	usage := w.usage
+	sb.WriteString("(")
+	sb.WriteString(w.sql)
+	for _, o := range others {
+		sb.WriteString(operator)
+		sb.WriteString(o.sql)
+		params = append(params, o.params...)
+		aliases = append(aliases, o.paddedAliases()...)
+		joins = appendUnique(joins, o.joins...)
+		usage = usage.merge(o.usage)
+	}
+	sb.WriteString(")")
+	return &WherePredicate{
+		sql:        sb.String(),
+		params:     params,
+		aliases:    aliases,
+		style:      w.style,
+		offset:     w.offset,
+		aliasNames: w.aliasNames,
+		delim:      w.delim,
+		concat:     w.concat,
+		usage:      usage,
+		joins:      joins,
+	}
+}
+
+// paddedAliases returns a copy of the aliases with one entry per parameter
+func (w *WherePredicate) paddedAliases() []string {
+	aliases := make([]string, len(w.params))
+	copy(aliases, w.aliases)
+	return aliases
+}
+
+// Joins returns the joins needed by the predicate separated by spaces,
+// it is empty if no related field is used
+func (w *WherePredicate) Joins() string {
+	return renderSql(w.delim, w.concat, strings.Join(w.joins, " "))
 }
 
 // Parameters return the underlying parameters
@@ -35,33 +272,189 @@ func (w *WherePredicate) Parameters() []interface{} {
 
 // String simply returns the query string and paremeters
 func (w *WherePredicate) String() string {
-	return fmt.Sprintf("%s (%+v)", w.sql, w.params)
+	return fmt.Sprintf("%s (%+v)", w.render(), w.params)
 }
 
 // OrderByClause represents a order by clause
 // without the order by keyword
 type OrderByClause struct {
-	sql string
+	sql   string
+	terms []orderTerm
+	joins []string
+	delim delimiterStyle
+}
+
+// Joins returns the joins needed by the clause separated by spaces,
+// it is empty if no related field is used
+func (o *OrderByClause) Joins() string {
+	return renderIdentifiers(o.delim, strings.Join(o.joins, " "))
+}
+
+func (o *OrderByClause) render() string {
+	return renderIdentifiers(o.delim, o.sql)
 }
 
 // ToSql returns the query string and the parameters
 // satisfies sqlizer https://pkg.go.dev/github.com/masterminds/squirrel#Sqlizer
 func (o *OrderByClause) ToSql() (string, []interface{}, error) {
-	return o.sql, nil, nil
+	return o.render(), nil, nil
 }
 
 // Sql returns the underlying sql string
 func (o *OrderByClause) Sql() string {
-	return o.sql
+	return o.render()
 }
 
 // String simply returns the query string
 func (o *OrderByClause) String() string {
-	return o.sql
+	return o.render()
 }
 
 // Query satisfies querier interface from ent
 // https://pkg.go.dev/entgo.io/ent@v0.12.4/dialect/sql#Querier
 func (o *OrderByClause) Query() (string, []any) {
-	return o.sql, []any{}
+	return o.render(), []any{}
+}
+
+// LimitClause represents a limit clause including its keywords,
+// it is empty if neither a limit nor an offset applies
+type LimitClause struct {
+	sql  string
+	page Page
+}
+
+// Page returns the page after the default and maximum page size were applied
+func (l *LimitClause) Page() Page {
+	return l.page
+}
+
+// ToSql returns the query string and the parameters
+// satisfies sqlizer https://pkg.go.dev/github.com/masterminds/squirrel#Sqlizer
+func (l *LimitClause) ToSql() (string, []interface{}, error) {
+	return l.sql, nil, nil
+}
+
+// Sql returns the underlying sql string
+func (l *LimitClause) Sql() string {
+	return l.sql
+}
+
+// String simply returns the query string
+func (l *LimitClause) String() string {
+	return l.sql
+}
+
+// Query satisfies querier interface from ent
+// https://pkg.go.dev/entgo.io/ent@v0.12.4/dialect/sql#Querier
+func (l *LimitClause) Query() (string, []any) {
+	return l.sql, []any{}
+}
+
+// Statement represents a complete sql statement with its parameters
+type Statement struct {
+	p *WherePredicate
+}
+
+// ToSql returns the query string and the parameters
+// satisfies sqlizer https://pkg.go.dev/github.com/masterminds/squirrel#Sqlizer
+// with named parameters the parameters are sql.NamedArg
+func (s *Statement) ToSql() (string, []interface{}, error) {
+	return s.p.ToSql()
+}
+
+// Query satisfies querier interface from ent
+// https://pkg.go.dev/entgo.io/ent@v0.12.4/dialect/sql#Querier
+func (s *Statement) Query() (string, []any) {
+	return s.p.Query()
+}
+
+// NamedArgs returns the parameters as sql.NamedArg
+// named like the placeholders of named parameter styles
+func (s *Statement) NamedArgs() []sql.NamedArg {
+	return s.p.NamedArgs()
+}
+
+// NamedParams returns the parameters as map as used by sqlx named queries
+// named like the placeholders of named parameter styles
+func (s *Statement) NamedParams() map[string]interface{} {
+	return s.p.NamedParams()
+}
+
+// Sql returns the underlying sql string
+func (s *Statement) Sql() string {
+	return s.p.Sql()
+}
+
+// Parameters return the underlying parameters
+func (s *Statement) Parameters() []interface{} {
+	return s.p.Parameters()
+}
+
+// String simply returns the query string and paremeters
+func (s *Statement) String() string {
+	return s.p.String()
+}
+
+// SelectClause represents a column list
+// without the select keyword
+type SelectClause struct {
+	sql     string
+	aliases []string
+	joins   []string
+	delim   delimiterStyle
+}
+
+// Joins returns the joins needed by the clause separated by spaces,
+// it is empty if no related field is used
+func (s *SelectClause) Joins() string {
+	return renderIdentifiers(s.delim, strings.Join(s.joins, " "))
+}
+
+// Aliases returns the aliases of the selected fields in order
+func (s *SelectClause) Aliases() []string {
+	return s.aliases
+}
+
+func (s *SelectClause) render() string {
+	return renderIdentifiers(s.delim, s.sql)
+}
+
+// ToSql returns the query string and the parameters
+// satisfies sqlizer https://pkg.go.dev/github.com/masterminds/squirrel#Sqlizer
+func (s *SelectClause) ToSql() (string, []interface{}, error) {
+	return s.render(), nil, nil
+}
+
+// Sql returns the underlying sql string
+func (s *SelectClause) Sql() string {
+	return s.render()
+}
+
+// String simply returns the query string
+func (s *SelectClause) String() string {
+	return s.render()
+}
+
+// Query satisfies querier interface from ent
+// https://pkg.go.dev/entgo.io/ent@v0.12.4/dialect/sql#Querier
+func (s *SelectClause) Query() (string, []any) {
+	return s.render(), []any{}
+}
+
+// AggregateClause holds the select list and group by clause of an aggregate query
+type AggregateClause struct {
+	sel     *SelectClause
+	groupBy string
+	fields  FieldMapping
+}
+
+// Select returns the column list of the grouped fields followed by the aggregates
+func (c *AggregateClause) Select() *SelectClause {
+	return c.sel
+}
+
+// GroupBy returns the group by column list without the group by keyword,
+// it is empty if nothing is grouped
+func (c *AggregateClause) GroupBy() string {
+	return renderIdentifiers(c.sel.delim, c.groupBy)
 }
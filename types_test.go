@@ -0,0 +1,203 @@
+package fiqlsqladapter
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredicateAndRenumbersPostgres(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("id==1;tx==a")
+	assert.NoError(t, err)
+	b, err := adp.Where("amt=gt=2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := a.And(b, RawPredicate(`"state" = ?`, "open")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(("ID" = $1 AND "Tx" LIKE $2) AND ("amount" > $3) AND "state" = $4)`, s)
+	assert.Equal(t, []interface{}{1, "a", 2.0, "open"}, args)
+}
+
+func TestRawPredicateEscapedQuestionMark(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	raw := RawPredicate(`("tags" ??| ? AND "note" <> 'why??')`, "{a,b}")
+	s, args, err := a.And(raw).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `(("ID" = $1) AND ("tags" ?| $2 AND "note" <> 'why?'))`, s)
+	assert.Equal(t, []interface{}{1, "{a,b}"}, args)
+}
+
+func TestPredicateOrNotMSSQL(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL())
+	a, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	b, err := adp.Where("id==2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(([ID] = @1) OR (NOT ([ID] = @2)))`, a.Or(b.Not()).Sql())
+}
+
+func TestPredicateWithOffset(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("id==1;id==2")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("ID" = $3 AND "ID" = $4)`, a.WithOffset(2).Sql())
+	assert.Equal(t, `("ID" = $1 AND "ID" = $2)`, a.Sql())
+}
+
+func TestPredicateRebind(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("id==1;tx==a*")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := a.ToSqlFor(WithDialect(DialectSQLite))
+	assert.NoError(t, err)
+	assert.Equal(t, `("ID" = ? AND "Tx" LIKE ? || '%')`, s)
+	assert.Equal(t, []interface{}{1, "a"}, args)
+	mssql, err := a.Rebind(WithDialectMSSQL())
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `([ID] = @1 AND [Tx] LIKE CONCAT(@2,'%'))`, mssql.Sql())
+	maria, err := a.Rebind(WithDialectMariaDB())
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, "(`ID` = ? AND `Tx` LIKE CONCAT(?,'%'))", maria.Sql())
+	assert.Equal(t, `("ID" = $1 AND "Tx" LIKE CONCAT($2,'%'))`, a.Sql())
+}
+
+func TestPredicateRebindUnknownDialect(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	_, err = a.Rebind(WithDialect("nope"))
+	assert.Error(t, err)
+	_, _, err = a.ToSqlFor(WithDialect("nope"))
+	assert.Error(t, err)
+}
+
+func TestPredicateRebindUnsupportedConstructs(t *testing.T) {
+	adp := NewAdapterFor(&analyticsRowStruct{}, WithDialectPostgres(), WithCaseInsensitiveLike())
+	tests := []struct {
+		query     string
+		construct string
+	}{
+		{"name==jo*", "ILIKE"},
+		{"tag==red", "array contains"},
+	}
+	for _, test := range tests {
+		a, err := adp.Where(test.query)
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+		_, _, err = a.ToSqlFor(WithDialect(DialectSQLite))
+		var e *Error
+		assert.True(t, errors.As(err, &e))
+		if e == nil {
+			return
+		}
+		assert.Equal(t, ErrorKindUnsupportedByDialect, e.Kind)
+		assert.Equal(t, test.construct, e.Value)
+		_, err = a.Rebind(WithDialect(DialectDuckDB))
+		if test.construct == "ILIKE" {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	a, err := NewAdapterFor(&analyticsRowStruct{}, WithDialectClickHouse()).Where("cre=ge=2022-09-16T10:15:04Z")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	_, err = a.Rebind(WithDialectPostgres())
+	assert.EqualError(t, err, "not supported by dialect: time parameters")
+
+	a, err = NewAdapterFor(&myFunnyRowStruct{}, WithDialectOracle()).Where(`tx==\`)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	_, err = a.Rebind(WithDialectPostgres())
+	assert.EqualError(t, err, "not supported by dialect: empty string")
+	_, err = a.Rebind(WithDialectOracle())
+	assert.NoError(t, err)
+}
+
+func TestPredicateRebindPlainPredicate(t *testing.T) {
+	adp := NewAdapterFor(&analyticsRowStruct{}, WithDialectPostgres())
+	a, err := adp.Where("name==jo*;cre=ge=2022-09-16T10:15:04Z")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, _, err := a.ToSqlFor(WithDialect(DialectSQLite))
+	assert.NoError(t, err)
+	assert.Equal(t, `("name" LIKE ? || '%' AND "created_at" >= ?)`, s)
+}
+
+func TestPredicateNamedParams(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectPostgres(), WithNamedParams())
+	a, err := adp.Where("id==1;tx==a")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	s, args, err := a.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `("ID" = :p1 AND "Tx" LIKE :p2)`, s)
+	assert.Equal(t, []interface{}{sql.Named("p1", 1), sql.Named("p2", "a")}, args)
+	assert.Equal(t, map[string]interface{}{"p1": 1, "p2": "a"}, a.NamedParams())
+}
+
+func TestNamedParamsBeforeDialect(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithNamedParams(), WithAliasParamNames(), WithDialectOracle())
+	a, err := adp.Where("id==1")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `("ID" = :id_1)`, a.Sql())
+}
+
+func TestPredicateAliasNamedParamsMSSQL(t *testing.T) {
+	adp := NewAdapterFor(&myFunnyRowStruct{}, WithDialectMSSQL(), WithNamedParamsAt(), WithAliasParamNames(), WithScope("tenant_id", 3))
+	a, err := adp.Where("amt=gt=1,amt=lt=0")
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(([amount] > @amt_1 OR [amount] < @amt_2) AND [tenant_id] = @tenant_id_3)`, a.Sql())
+	assert.Equal(t, []sql.NamedArg{sql.Named("amt_1", 1.0), sql.Named("amt_2", 0.0), sql.Named("tenant_id_3", 3)}, a.NamedArgs())
+	sqlite, err := a.Rebind(WithDialectSQLite())
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	assert.Equal(t, `(("amount" > ? OR "amount" < ?) AND "tenant_id" = ?)`, sqlite.Sql())
+}
@@ -0,0 +1,56 @@
+package fiqlsqladapter
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// WildcardPolicy defines which wildcards may be used in string arguments,
+// a higher policy is stricter
+type WildcardPolicy int
+
+// WildcardsAllowed allows leading and trailing wildcards
+const WildcardsAllowed WildcardPolicy = 0
+
+// WildcardsNoLeading forbids leading wildcards as they prevent index usage
+const WildcardsNoLeading WildcardPolicy = 1
+
+// WildcardsForbidden forbids any wildcards
+const WildcardsForbidden WildcardPolicy = 2
+
+// WithWildcardPolicy sets the wildcard policy for all fields,
+// a stricter policy defined on a field takes precedence
+func WithWildcardPolicy(policy WildcardPolicy) func(*Adapter) {
+	return func(a *Adapter) {
+		a.wildcards = policy
+	}
+}
+
+// WithMinWildcardLength requires arguments using a wildcard to carry
+// at least n literal characters, a longer minimum defined on a field takes precedence
+func WithMinWildcardLength(n int) func(*Adapter) {
+	return func(a *Adapter) {
+		a.minWildcardLength = n
+	}
+}
+
+func (t *whereBuilder) checkWildcards(leading, trailing bool, literal string) *Error {
+	if !leading && !trailing {
+		return nil
+	}
+	policy := t.wildcards
+	if t.lastSelector.Wildcards > policy {
+		policy = t.lastSelector.Wildcards
+	}
+	if policy == WildcardsForbidden || (policy == WildcardsNoLeading && leading) {
+		return newError(ErrorKindWildcardNotAllowed, t.lastSelector.Alias, nil)
+	}
+	minLength := t.minWildcardLength
+	if t.lastSelector.MinWildcardLength > minLength {
+		minLength = t.lastSelector.MinWildcardLength
+	}
+	if utf8.RuneCountInString(literal) < minLength {
+		return newError(ErrorKindWildcardTooShort, strconv.Itoa(minLength), nil)
+	}
+	return nil
+}